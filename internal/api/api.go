@@ -0,0 +1,430 @@
+// Pacote api define o contrato compartilhado entre o Serviço A e o Serviço B
+// Centraliza os tipos de requisição e resposta trafegados entre os serviços,
+// evitando que cada serviço mantenha sua própria cópia das estruturas
+package api
+
+import (
+	"cep-weather/internal/problem"
+	"cep-weather/internal/units"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ErrInvalidResponse indica que uma resposta de clima não respeita o contrato
+var ErrInvalidResponse = errors.New("invalid weather response")
+
+// WeatherRequest define o payload JSON de consulta de clima por CEP
+// Formato: {"cep": "29902555"}
+type WeatherRequest struct {
+	CEP      string `json:"cep"`                // CEP a ser consultado (string com 8 dígitos)
+	Extended bool   `json:"extended,omitempty"` // Inclui as condições detalhadas na resposta (opcional)
+}
+
+// WeatherResponse define a resposta de clima devolvida pelo Serviço B
+// Formato de resposta conforme especificação dos requisitos
+type WeatherResponse struct {
+	City  string  `json:"city" xml:"city"`     // Nome da cidade encontrada via ViaCEP
+	TempC float64 `json:"temp_C" xml:"temp_C"` // Temperatura em Celsius (da WeatherAPI)
+	TempF float64 `json:"temp_F" xml:"temp_F"` // Temperatura em Fahrenheit
+	TempK float64 `json:"temp_K" xml:"temp_K"` // Temperatura em Kelvin
+
+	// Condições detalhadas, presentes apenas quando solicitadas (WeatherRequest.Extended)
+	Conditions *Conditions `json:"conditions,omitempty" xml:"conditions,omitempty"`
+}
+
+// Conditions detalha as condições atuais do tempo na cidade
+// As conversões de unidade seguem as mesmas regras da temperatura principal
+type Conditions struct {
+	FeelsLike Temperature `json:"feels_like" xml:"feels_like"` // Sensação térmica
+	Humidity  int         `json:"humidity" xml:"humidity"`     // Umidade relativa do ar, em porcentagem
+	Wind      Wind        `json:"wind" xml:"wind"`             // Vento
+	Pressure  Pressure    `json:"pressure" xml:"pressure"`     // Pressão atmosférica
+	UV        float64     `json:"uv" xml:"uv"`                 // Índice UV
+	Text      string      `json:"text" xml:"text"`             // Descrição da condição do tempo (ex: "Partly cloudy")
+}
+
+// Validate verifica se as condições respeitam o contrato
+// - os valores numéricos devem ser finitos
+// - a umidade deve estar entre 0 e 100
+func (c Conditions) Validate() error {
+	values := map[string]float64{
+		"feels_like.temp_C": c.FeelsLike.C, "feels_like.temp_F": c.FeelsLike.F, "feels_like.temp_K": c.FeelsLike.K,
+		"wind.kph": c.Wind.Kph, "wind.m_s": c.Wind.MS, "wind.mph": c.Wind.Mph,
+		"pressure.hPa": c.Pressure.Hpa, "pressure.inHg": c.Pressure.InHg,
+		"uv": c.UV,
+	}
+	for name, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: conditions.%s is not a finite number", ErrInvalidResponse, name)
+		}
+	}
+	if c.Humidity < 0 || c.Humidity > 100 {
+		return fmt.Errorf("%w: conditions.humidity must be between 0 and 100", ErrInvalidResponse)
+	}
+	return nil
+}
+
+// Wind representa a velocidade e a direção do vento
+type Wind struct {
+	Kph       float64 `json:"kph" xml:"kph"`                                 // Velocidade em km/h
+	MS        float64 `json:"m_s" xml:"m_s"`                                 // Velocidade em m/s
+	Mph       float64 `json:"mph" xml:"mph"`                                 // Velocidade em milhas por hora
+	Direction string  `json:"direction,omitempty" xml:"direction,omitempty"` // Direção (ex: "NE")
+}
+
+// NewWind converte a velocidade do vento em km/h para as três unidades (ver units.Speed)
+func NewWind(kph float64, direction string) Wind {
+	s := units.KilometersPerHour(kph)
+	return Wind{
+		Kph:       units.Round(s.KilometersPerHour()),
+		MS:        units.Round(s.MetersPerSecond()),
+		Mph:       units.Round(s.MilesPerHour()),
+		Direction: direction,
+	}
+}
+
+// Pressure representa a pressão atmosférica
+type Pressure struct {
+	Hpa  float64 `json:"hPa" xml:"hPa"`   // Hectopascals (equivalente a milibares)
+	InHg float64 `json:"inHg" xml:"inHg"` // Polegadas de mercúrio
+}
+
+// NewPressure converte a pressão em hectopascals para as duas unidades (ver units.Pressure)
+func NewPressure(hpa float64) Pressure {
+	p := units.Hectopascals(hpa)
+	return Pressure{Hpa: units.Round(p.Hectopascals()), InHg: units.Round(p.InchesOfMercury())}
+}
+
+// NewWeatherResponse monta a resposta de clima a partir da temperatura em Celsius
+// aplicando as conversões do contrato (ver NewTemperature)
+func NewWeatherResponse(city string, tempC float64) WeatherResponse {
+	t := NewTemperature(tempC)
+	return WeatherResponse{City: city, TempC: t.C, TempF: t.F, TempK: t.K}
+}
+
+// Validate verifica se a resposta respeita o contrato
+// - city deve ser não vazio
+// - as temperaturas devem ser números finitos
+// - as condições detalhadas, quando presentes, devem ser válidas
+func (w WeatherResponse) Validate() error {
+	if w.City == "" {
+		return fmt.Errorf("%w: city is empty", ErrInvalidResponse)
+	}
+	for name, v := range map[string]float64{"temp_C": w.TempC, "temp_F": w.TempF, "temp_K": w.TempK} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: %s is not a finite number", ErrInvalidResponse, name)
+		}
+	}
+	if w.Conditions != nil {
+		return w.Conditions.Validate()
+	}
+	return nil
+}
+
+// DecodeWeatherResponse decodifica e valida uma resposta de clima em JSON
+//
+// Diferente de um json.Decode simples, campos ausentes ou nulos são tratados
+// como erro: uma temperatura ausente não pode ser confundida com 0 °C.
+// Todos os erros retornados envolvem ErrInvalidResponse.
+func DecodeWeatherResponse(r io.Reader) (WeatherResponse, error) {
+	var raw struct {
+		City  *string  `json:"city"`
+		TempC *float64 `json:"temp_C"`
+		TempF *float64 `json:"temp_F"`
+		TempK *float64 `json:"temp_K"`
+
+		Conditions *Conditions `json:"conditions"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return WeatherResponse{}, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+
+	switch {
+	case raw.City == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing city", ErrInvalidResponse)
+	case raw.TempC == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing temp_C", ErrInvalidResponse)
+	case raw.TempF == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing temp_F", ErrInvalidResponse)
+	case raw.TempK == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing temp_K", ErrInvalidResponse)
+	}
+
+	resp := WeatherResponse{City: *raw.City, TempC: *raw.TempC, TempF: *raw.TempF, TempK: *raw.TempK, Conditions: raw.Conditions}
+	if err := resp.Validate(); err != nil {
+		return WeatherResponse{}, err
+	}
+	return resp, nil
+}
+
+// MaxBatchSize é a quantidade máxima de CEPs aceita em uma consulta em lote
+const MaxBatchSize = 500
+
+// BatchRequest define o payload JSON de consulta de clima em lote
+// Formato: {"ceps": ["29902555", "01310-100"]}
+type BatchRequest struct {
+	CEPs []string `json:"ceps"` // CEPs a serem consultados (até MaxBatchSize)
+}
+
+// Validate verifica se o lote possui entre 1 e MaxBatchSize CEPs
+func (b BatchRequest) Validate() error {
+	if len(b.CEPs) == 0 {
+		return errors.New("ceps must contain at least one zipcode")
+	}
+	if len(b.CEPs) > MaxBatchSize {
+		return fmt.Errorf("ceps must contain at most %d zipcodes", MaxBatchSize)
+	}
+	return nil
+}
+
+// BatchItem representa o resultado da consulta de um CEP dentro do lote
+// Exatamente um dos campos Weather ou Error é preenchido
+type BatchItem struct {
+	CEP     string           `json:"cep"`               // CEP como informado pelo cliente
+	Weather *WeatherResponse `json:"weather,omitempty"` // Clima, em caso de sucesso
+	Error   *problem.Problem `json:"error,omitempty"`   // Erro no formato RFC 7807, em caso de falha
+}
+
+// Validate verifica se o item respeita o contrato
+func (i BatchItem) Validate() error {
+	switch {
+	case i.Weather != nil && i.Error != nil:
+		return fmt.Errorf("%w: item %q has both weather and error", ErrInvalidResponse, i.CEP)
+	case i.Weather != nil:
+		return i.Weather.Validate()
+	case i.Error == nil || i.Error.Code == "":
+		return fmt.Errorf("%w: item %q has neither weather nor error", ErrInvalidResponse, i.CEP)
+	}
+	return nil
+}
+
+// BatchResponse define a resposta de uma consulta em lote
+// Os resultados seguem a mesma ordem dos CEPs da requisição
+type BatchResponse struct {
+	Results []BatchItem `json:"results"`
+}
+
+// DecodeBatchResponse decodifica e valida uma resposta em lote em JSON
+// A quantidade de resultados deve corresponder à quantidade de CEPs enviados
+func DecodeBatchResponse(r io.Reader, size int) (BatchResponse, error) {
+	var resp BatchResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return BatchResponse{}, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := resp.Validate(size); err != nil {
+		return BatchResponse{}, err
+	}
+	return resp, nil
+}
+
+// Validate verifica se a resposta traz um resultado válido para cada um dos size CEPs enviados
+func (b BatchResponse) Validate(size int) error {
+	if len(b.Results) != size {
+		return fmt.Errorf("%w: expected %d results, got %d", ErrInvalidResponse, size, len(b.Results))
+	}
+	for _, item := range b.Results {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Temperature representa uma temperatura nas três escalas do contrato
+type Temperature struct {
+	C float64 `json:"temp_C" xml:"temp_C"` // Celsius
+	F float64 `json:"temp_F" xml:"temp_F"` // Fahrenheit
+	K float64 `json:"temp_K" xml:"temp_K"` // Kelvin
+}
+
+// NewTemperature converte uma temperatura em Celsius para as três escalas
+// As conversões e o arredondamento seguem o pacote units:
+// - Fahrenheit: F = C * 1.8 + 32
+// - Kelvin: K = C + 273.15 (C + 273 com units.LegacyKelvin)
+func NewTemperature(c float64) Temperature {
+	t := units.Celsius(c)
+	return Temperature{
+		C: units.Round(t.Celsius()),
+		F: units.Round(t.Fahrenheit()),
+		K: units.Round(t.Kelvin()),
+	}
+}
+
+// Limites da quantidade de dias da previsão
+const (
+	DefaultForecastDays = 3  // Dias de previsão quando não informado
+	MaxForecastDays     = 14 // Limite da WeatherAPI
+)
+
+// ForecastRequest define o payload JSON de consulta da previsão por CEP
+// Formato: {"cep": "29902555", "days": 3}
+type ForecastRequest struct {
+	CEP  string `json:"cep"`  // CEP a ser consultado
+	Days int    `json:"days"` // Quantidade de dias (1 a MaxForecastDays; 0 usa DefaultForecastDays)
+}
+
+// Validate verifica se a quantidade de dias está dentro dos limites
+func (f ForecastRequest) Validate() error {
+	if f.Days < 0 || f.Days > MaxForecastDays {
+		return fmt.Errorf("days must be between 1 and %d", MaxForecastDays)
+	}
+	return nil
+}
+
+// ForecastDay representa a previsão de um dia
+type ForecastDay struct {
+	Date         string      `json:"date"`           // Data (AAAA-MM-DD) no fuso horário da cidade
+	Min          Temperature `json:"min"`            // Temperatura mínima
+	Max          Temperature `json:"max"`            // Temperatura máxima
+	ChanceOfRain int         `json:"chance_of_rain"` // Probabilidade de chuva, em porcentagem
+	PrecipMM     float64     `json:"precip_mm"`      // Precipitação total prevista, em milímetros
+}
+
+// ForecastResponse define a resposta da previsão de uma cidade
+// Os dias seguem a ordem cronológica, a partir de hoje
+type ForecastResponse struct {
+	City string        `json:"city"`
+	Days []ForecastDay `json:"days"`
+}
+
+// AirQualityRequest define o payload JSON de consulta da qualidade do ar por CEP
+// Formato: {"cep": "29902555"}
+type AirQualityRequest struct {
+	CEP string `json:"cep"` // CEP a ser consultado
+}
+
+// Categorias de qualidade do ar, conforme o índice da US EPA
+// Os valores são estáveis e podem ser usados para tratamento programático
+const (
+	AirGood               = "good"                           // Índice 1
+	AirModerate           = "moderate"                       // Índice 2
+	AirUnhealthySensitive = "unhealthy_for_sensitive_groups" // Índice 3
+	AirUnhealthy          = "unhealthy"                      // Índice 4
+	AirVeryUnhealthy      = "very_unhealthy"                 // Índice 5
+	AirHazardous          = "hazardous"                      // Índice 6
+	AirUnknown            = "unknown"                        // Índice ausente ou fora da escala
+)
+
+// AirQualityCategory retorna a categoria correspondente ao índice da US EPA (1 a 6)
+func AirQualityCategory(index int) string {
+	switch index {
+	case 1:
+		return AirGood
+	case 2:
+		return AirModerate
+	case 3:
+		return AirUnhealthySensitive
+	case 4:
+		return AirUnhealthy
+	case 5:
+		return AirVeryUnhealthy
+	case 6:
+		return AirHazardous
+	default:
+		return AirUnknown
+	}
+}
+
+// AirQualityResponse define a resposta da qualidade do ar de uma cidade
+// As concentrações dos poluentes são expressas em μg/m³
+type AirQualityResponse struct {
+	City     string  `json:"city"`
+	PM25     float64 `json:"pm2_5"`    // Material particulado fino (PM2.5)
+	PM10     float64 `json:"pm10"`     // Material particulado inalável (PM10)
+	O3       float64 `json:"o3"`       // Ozônio
+	Index    int     `json:"index"`    // Índice de qualidade do ar da US EPA (1 a 6)
+	Category string  `json:"category"` // Categoria do índice (ver AirQualityCategory)
+	UV       float64 `json:"uv"`       // Índice UV
+}
+
+// AlertsRequest define o payload JSON de consulta dos alertas meteorológicos por CEP
+// Formato: {"cep": "29902555"}
+type AlertsRequest struct {
+	CEP string `json:"cep"` // CEP a ser consultado
+}
+
+// Alert representa um alerta meteorológico ativo
+type Alert struct {
+	Event       string `json:"event"`                 // Tipo do evento (ex: "Heavy Rain")
+	Severity    string `json:"severity"`              // extreme, severe, moderate, minor ou unknown
+	Headline    string `json:"headline,omitempty"`    // Resumo do alerta
+	Start       string `json:"start,omitempty"`       // Início da vigência (RFC 3339)
+	End         string `json:"end,omitempty"`         // Fim da vigência (RFC 3339)
+	Description string `json:"description,omitempty"` // Descrição completa
+}
+
+// AlertsResponse define a resposta dos alertas de uma cidade
+// Uma lista vazia indica que não há alertas ativos
+type AlertsResponse struct {
+	City   string  `json:"city"`
+	Alerts []Alert `json:"alerts"`
+}
+
+// MinHistoryDate é a data mais antiga disponível no histórico da WeatherAPI
+const MinHistoryDate = "2010-01-01"
+
+// dateLayout é o formato das datas do histórico (AAAA-MM-DD)
+const dateLayout = "2006-01-02"
+
+// ParseHistoryDate interpreta a data de uma consulta ao histórico no fuso
+// horário da localização
+//
+// A data pode ser informada como dia local (AAAA-MM-DD) ou como instante
+// RFC 3339 (ex: "2024-05-01T15:30:00Z"). No segundo caso, o instante é
+// convertido para o fuso da localização, o que define o dia consultado e a
+// hora local correspondente.
+//
+// Apenas dias já encerrados no local são aceitos: o dia de hoje ainda pode
+// mudar, e os resultados do histórico são mantidos em cache indefinidamente.
+//
+// Parâmetros:
+//   - raw: Data informada pelo cliente
+//   - loc: Fuso horário da localização
+//   - now: Instante atual, usado para rejeitar datas que não estão no passado
+//
+// Retorna:
+//   - string: Dia local no formato AAAA-MM-DD
+//   - time.Time: Instante informado, no fuso da localização (zero quando a data é um dia)
+//   - error: Erro caso a data seja inválida, anterior a MinHistoryDate ou não esteja no passado
+func ParseHistoryDate(raw string, loc *time.Location, now time.Time) (string, time.Time, error) {
+	var date string
+	var at time.Time
+	if d, err := time.ParseInLocation(dateLayout, raw, loc); err == nil {
+		date = d.Format(dateLayout)
+	} else if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		at = t.In(loc)
+		date = at.Format(dateLayout)
+	} else {
+		return "", time.Time{}, errors.New("date must be formatted as YYYY-MM-DD or RFC 3339")
+	}
+
+	// Datas no formato AAAA-MM-DD podem ser comparadas como texto
+	if date < MinHistoryDate {
+		return "", time.Time{}, fmt.Errorf("date must be on or after %s", MinHistoryDate)
+	}
+	if date >= now.In(loc).Format(dateLayout) {
+		return "", time.Time{}, errors.New("date must be before today at the location")
+	}
+	return date, at, nil
+}
+
+// HistoryHour representa a temperatura registrada em uma hora do dia
+type HistoryHour struct {
+	Time string `json:"time"` // Horário local (RFC 3339, com o deslocamento do fuso)
+	Temperature
+}
+
+// HistoryResponse define a resposta do histórico de temperatura de uma cidade
+type HistoryResponse struct {
+	City     string       `json:"city"`
+	Date     string       `json:"date"`         // Dia consultado (AAAA-MM-DD), no fuso da localização
+	TimeZone string       `json:"timezone"`     // Fuso horário IANA da localização
+	Min      Temperature  `json:"min"`          // Temperatura mínima do dia
+	Max      Temperature  `json:"max"`          // Temperatura máxima do dia
+	Avg      Temperature  `json:"avg"`          // Temperatura média do dia
+	At       *HistoryHour `json:"at,omitempty"` // Temperatura na hora do instante informado (apenas para datas RFC 3339)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/problem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeWeatherResponse(t *testing.T) {
+	resp, err := DecodeWeatherResponse(strings.NewReader(`{"city":"Linhares","temp_C":0,"temp_F":32,"temp_K":273}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.City != "Linhares" || resp.TempC != 0 || resp.TempF != 32 || resp.TempK != 273 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestDecodeWeatherResponse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"malformed":    `{"city":`,
+		"empty city":   `{"city":"","temp_C":1,"temp_F":1,"temp_K":1}`,
+		"missing city": `{"temp_C":1,"temp_F":1,"temp_K":1}`,
+		"missing temp": `{"city":"Linhares","temp_C":1,"temp_F":1}`,
+		"null temp":    `{"city":"Linhares","temp_C":null,"temp_F":1,"temp_K":1}`,
+		"string temp":  `{"city":"Linhares","temp_C":"28","temp_F":1,"temp_K":1}`,
+	}
+	for name, body := range cases {
+		if _, err := DecodeWeatherResponse(strings.NewReader(body)); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("%s: expected ErrInvalidResponse, got %v", name, err)
+		}
+	}
+}
+
+func TestWeatherFromProto_Conditions(t *testing.T) {
+	want := NewWeatherResponse("Linhares", 28.5)
+	want.Conditions = &Conditions{
+		FeelsLike: NewTemperature(31),
+		Humidity:  78,
+		Wind:      NewWind(36, "NE"),
+		Pressure:  NewPressure(1013.25),
+		UV:        6,
+		Text:      "Partly cloudy",
+	}
+
+	got, err := WeatherFromProto(want.Proto())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Conditions == nil || *got.Conditions != *want.Conditions {
+		t.Fatalf("unexpected round trip: %+v", got.Conditions)
+	}
+	if got, _ := WeatherFromProto(NewWeatherResponse("Linhares", 28.5).Proto()); got.Conditions != nil {
+		t.Fatalf("expected no conditions, got %+v", got.Conditions)
+	}
+
+	cases := map[string]func(*weatherpb.Conditions){
+		"missing feels_like": func(c *weatherpb.Conditions) { c.FeelsLike = nil },
+		"missing wind":       func(c *weatherpb.Conditions) { c.Wind = nil },
+		"missing pressure":   func(c *weatherpb.Conditions) { c.Pressure = nil },
+		"humidity":           func(c *weatherpb.Conditions) { c.Humidity = 120 },
+	}
+	for name, mutate := range cases {
+		m := want.Proto()
+		mutate(m.Conditions)
+		if _, err := WeatherFromProto(m); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("%s: expected ErrInvalidResponse, got %v", name, err)
+		}
+	}
+}
+
+func TestBatchResponseFromProto(t *testing.T) {
+	weather := WeatherResponse{City: "Linhares", TempC: 28.5, TempF: 83.3, TempK: 301.5}
+	want := []BatchItem{
+		{CEP: "29902555", Weather: &weather},
+		{CEP: "123", Error: &problem.Problem{Code: "invalid_zipcode", Status: 422, Title: "Invalid zipcode"}},
+	}
+	m := &weatherpb.BatchGetWeatherResponse{}
+	for _, item := range want {
+		m.Results = append(m.Results, item.Proto())
+	}
+
+	resp, err := BatchResponseFromProto(m, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *resp.Results[0].Weather != weather || resp.Results[1].Error.Code != "invalid_zipcode" {
+		t.Fatalf("unexpected round trip: %+v", resp.Results)
+	}
+
+	if _, err := BatchResponseFromProto(m, 3); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse for size mismatch, got %v", err)
+	}
+	m.Results = append(m.Results, &weatherpb.BatchItem{Cep: "01310100"})
+	if _, err := BatchResponseFromProto(m, 3); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse for empty item, got %v", err)
+	}
+}
+
+func TestParseHistoryDate(t *testing.T) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-05-10 01:00 em São Paulo (UTC-3)
+	now := time.Date(2024, 5, 10, 4, 0, 0, 0, time.UTC)
+
+	date, at, err := ParseHistoryDate("2024-05-01", loc, now)
+	if err != nil || date != "2024-05-01" || !at.IsZero() {
+		t.Errorf("unexpected result for local date: %q %v %v", date, at, err)
+	}
+
+	// 02:30 UTC ainda é o dia anterior em São Paulo
+	date, at, err = ParseHistoryDate("2024-05-02T02:30:00Z", loc, now)
+	if err != nil || date != "2024-05-01" || at.Format(time.RFC3339) != "2024-05-01T23:30:00-03:00" {
+		t.Errorf("unexpected result for instant: %q %v %v", date, at, err)
+	}
+
+	invalid := []string{
+		"",
+		"01/05/2024",
+		"2024-02-30",
+		"2009-12-31",
+		"2024-05-10",           // hoje no local
+		"2024-05-10T03:30:00Z", // 00:30 de hoje no local
+		"2030-01-01",
+	}
+	for _, raw := range invalid {
+		if _, _, err := ParseHistoryDate(raw, loc, now); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
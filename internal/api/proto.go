@@ -0,0 +1,168 @@
+package api
+
+import (
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/problem"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Conversões entre os tipos do contrato JSON e as mensagens do contrato gRPC
+// (weatherpb). As mensagens espelham os tipos JSON campo a campo: um campo novo
+// no contrato JSON precisa ser incluído em weather.proto e nestas conversões.
+// Toda resposta recebida por gRPC passa pelas mesmas validações das respostas
+// JSON.
+
+// Proto converte a resposta de clima para a mensagem gRPC
+func (w WeatherResponse) Proto() *weatherpb.Weather {
+	m := &weatherpb.Weather{City: w.City, TempC: w.TempC, TempF: w.TempF, TempK: w.TempK}
+	if c := w.Conditions; c != nil {
+		m.Conditions = &weatherpb.Conditions{
+			FeelsLike: &weatherpb.Temperature{TempC: c.FeelsLike.C, TempF: c.FeelsLike.F, TempK: c.FeelsLike.K},
+			Humidity:  int32(c.Humidity),
+			Wind:      &weatherpb.Wind{Kph: c.Wind.Kph, MS: c.Wind.MS, Mph: c.Wind.Mph, Direction: c.Wind.Direction},
+			Pressure:  &weatherpb.Pressure{Hpa: c.Pressure.Hpa, InHg: c.Pressure.InHg},
+			Uv:        c.UV,
+			Text:      c.Text,
+		}
+	}
+	return m
+}
+
+// WeatherFromProto converte e valida uma resposta de clima recebida por gRPC
+// Todos os erros retornados envolvem ErrInvalidResponse
+func WeatherFromProto(m *weatherpb.Weather) (WeatherResponse, error) {
+	if m == nil {
+		return WeatherResponse{}, fmt.Errorf("%w: missing weather", ErrInvalidResponse)
+	}
+	resp, err := weatherFromProto(m)
+	if err != nil {
+		return WeatherResponse{}, err
+	}
+	if err := resp.Validate(); err != nil {
+		return WeatherResponse{}, err
+	}
+	return resp, nil
+}
+
+// weatherFromProto converte a mensagem gRPC de clima, sem validar os valores
+//
+// Assim como em DecodeWeatherResponse, as partes ausentes das condições
+// detalhadas são tratadas como erro: uma sensação térmica ausente não pode ser
+// confundida com 0 °C.
+func weatherFromProto(m *weatherpb.Weather) (WeatherResponse, error) {
+	resp := WeatherResponse{City: m.GetCity(), TempC: m.GetTempC(), TempF: m.GetTempF(), TempK: m.GetTempK()}
+	c := m.GetConditions()
+	if c == nil {
+		return resp, nil
+	}
+	switch {
+	case c.GetFeelsLike() == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing conditions.feels_like", ErrInvalidResponse)
+	case c.GetWind() == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing conditions.wind", ErrInvalidResponse)
+	case c.GetPressure() == nil:
+		return WeatherResponse{}, fmt.Errorf("%w: missing conditions.pressure", ErrInvalidResponse)
+	}
+	resp.Conditions = &Conditions{
+		FeelsLike: Temperature{C: c.GetFeelsLike().GetTempC(), F: c.GetFeelsLike().GetTempF(), K: c.GetFeelsLike().GetTempK()},
+		Humidity:  int(c.GetHumidity()),
+		Wind:      Wind{Kph: c.GetWind().GetKph(), MS: c.GetWind().GetMS(), Mph: c.GetWind().GetMph(), Direction: c.GetWind().GetDirection()},
+		Pressure:  Pressure{Hpa: c.GetPressure().GetHpa(), InHg: c.GetPressure().GetInHg()},
+		UV:        c.GetUv(),
+		Text:      c.GetText(),
+	}
+	return resp, nil
+}
+
+// Proto converte o resultado de um CEP do lote para a mensagem gRPC
+func (i BatchItem) Proto() *weatherpb.BatchItem {
+	m := &weatherpb.BatchItem{Cep: i.CEP}
+	switch {
+	case i.Weather != nil:
+		m.Result = &weatherpb.BatchItem_Weather{Weather: i.Weather.Proto()}
+	case i.Error != nil:
+		m.Result = &weatherpb.BatchItem_Error{Error: problemToProto(*i.Error)}
+	}
+	return m
+}
+
+// BatchResponseFromProto converte e valida uma resposta em lote recebida por gRPC
+// A quantidade de resultados deve corresponder à quantidade de CEPs enviados
+func BatchResponseFromProto(m *weatherpb.BatchGetWeatherResponse, size int) (BatchResponse, error) {
+	resp := BatchResponse{Results: make([]BatchItem, 0, len(m.GetResults()))}
+	for _, item := range m.GetResults() {
+		converted := BatchItem{CEP: item.GetCep()}
+		if w := item.GetWeather(); w != nil {
+			weather, err := weatherFromProto(w)
+			if err != nil {
+				return BatchResponse{}, err
+			}
+			converted.Weather = &weather
+		}
+		if e := item.GetError(); e != nil {
+			p := problemFromProto(e)
+			converted.Error = &p
+		}
+		resp.Results = append(resp.Results, converted)
+	}
+	if err := resp.Validate(size); err != nil {
+		return BatchResponse{}, err
+	}
+	return resp, nil
+}
+
+// problemToProto converte um Problem (RFC 7807) para a mensagem gRPC
+func problemToProto(p problem.Problem) *weatherpb.Problem {
+	return &weatherpb.Problem{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   int32(p.Status),
+		Detail:   p.Detail,
+		Instance: p.Instance,
+		Code:     p.Code,
+		TraceId:  p.TraceID,
+	}
+}
+
+// problemFromProto converte a mensagem gRPC de volta para um Problem (RFC 7807)
+func problemFromProto(m *weatherpb.Problem) problem.Problem {
+	return problem.Problem{
+		Type:     m.GetType(),
+		Title:    m.GetTitle(),
+		Status:   int(m.GetStatus()),
+		Detail:   m.GetDetail(),
+		Instance: m.GetInstance(),
+		Code:     m.GetCode(),
+		TraceID:  m.GetTraceId(),
+	}
+}
+
+// GRPCCode retorna o código de status gRPC equivalente à categoria de erro
+// - 422 (CEP ou lote inválido): INVALID_ARGUMENT
+// - 404 (CEP não encontrado): NOT_FOUND
+// - 503/504: UNAVAILABLE/DEADLINE_EXCEEDED
+// - 429 (cota excedida): RESOURCE_EXHAUSTED
+// - 401/403 (chave de API): UNAUTHENTICATED/PERMISSION_DENIED
+// - Demais: INTERNAL
+func GRPCCode(k problem.Kind) codes.Code {
+	switch k {
+	case problem.InvalidZipcode, problem.InvalidBatch, problem.InvalidDays, problem.InvalidDate:
+		return codes.InvalidArgument
+	case problem.ZipcodeNotFound:
+		return codes.NotFound
+	case problem.UpstreamUnavailable:
+		return codes.Unavailable
+	case problem.UpstreamTimeout:
+		return codes.DeadlineExceeded
+	case problem.RateLimited:
+		return codes.ResourceExhausted
+	case problem.Unauthorized:
+		return codes.Unauthenticated
+	case problem.Forbidden:
+		return codes.PermissionDenied
+	default:
+		return codes.Internal
+	}
+}
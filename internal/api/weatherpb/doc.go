@@ -0,0 +1,5 @@
+// Pacote weatherpb contém o código Go gerado a partir de weather.proto
+// (contrato gRPC do WeatherService). Não edite os arquivos *.pb.go manualmente.
+package weatherpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative weather.proto
@@ -0,0 +1,1015 @@
+// Contrato gRPC entre o Serviço A e o Serviço B
+// Espelha os tipos JSON do pacote api (WeatherRequest, WeatherResponse,
+// BatchRequest e BatchResponse), para os chamadores internos que preferem gRPC
+//
+// Para regenerar o código Go após alterar este arquivo:
+//   go generate ./internal/api/weatherpb
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: weather.proto
+
+package weatherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GetWeatherRequest é a consulta de clima de um CEP
+type GetWeatherRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cep      string `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`            // CEP com 8 dígitos, com ou sem formatação
+	Extended bool   `protobuf:"varint,2,opt,name=extended,proto3" json:"extended,omitempty"` // Inclui as condições detalhadas na resposta (opcional)
+}
+
+func (x *GetWeatherRequest) Reset() {
+	*x = GetWeatherRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWeatherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWeatherRequest) ProtoMessage() {}
+
+func (x *GetWeatherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWeatherRequest.ProtoReflect.Descriptor instead.
+func (*GetWeatherRequest) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetWeatherRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *GetWeatherRequest) GetExtended() bool {
+	if x != nil {
+		return x.Extended
+	}
+	return false
+}
+
+// Weather é o clima atual da cidade do CEP
+type Weather struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	City  string  `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`                  // Nome da cidade encontrada via ViaCEP
+	TempC float64 `protobuf:"fixed64,2,opt,name=temp_c,json=tempC,proto3" json:"temp_c,omitempty"` // Temperatura em Celsius
+	TempF float64 `protobuf:"fixed64,3,opt,name=temp_f,json=tempF,proto3" json:"temp_f,omitempty"` // Temperatura em Fahrenheit
+	TempK float64 `protobuf:"fixed64,4,opt,name=temp_k,json=tempK,proto3" json:"temp_k,omitempty"` // Temperatura em Kelvin
+	// Condições detalhadas, presentes apenas quando solicitadas
+	// (GetWeatherRequest.extended)
+	Conditions *Conditions `protobuf:"bytes,5,opt,name=conditions,proto3" json:"conditions,omitempty"`
+}
+
+func (x *Weather) Reset() {
+	*x = Weather{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Weather) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Weather) ProtoMessage() {}
+
+func (x *Weather) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Weather.ProtoReflect.Descriptor instead.
+func (*Weather) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Weather) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Weather) GetTempC() float64 {
+	if x != nil {
+		return x.TempC
+	}
+	return 0
+}
+
+func (x *Weather) GetTempF() float64 {
+	if x != nil {
+		return x.TempF
+	}
+	return 0
+}
+
+func (x *Weather) GetTempK() float64 {
+	if x != nil {
+		return x.TempK
+	}
+	return 0
+}
+
+func (x *Weather) GetConditions() *Conditions {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+// Conditions detalha as condições atuais do tempo na cidade
+type Conditions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FeelsLike *Temperature `protobuf:"bytes,1,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"` // Sensação térmica
+	Humidity  int32        `protobuf:"varint,2,opt,name=humidity,proto3" json:"humidity,omitempty"`                   // Umidade relativa do ar, em porcentagem
+	Wind      *Wind        `protobuf:"bytes,3,opt,name=wind,proto3" json:"wind,omitempty"`                            // Vento
+	Pressure  *Pressure    `protobuf:"bytes,4,opt,name=pressure,proto3" json:"pressure,omitempty"`                    // Pressão atmosférica
+	Uv        float64      `protobuf:"fixed64,5,opt,name=uv,proto3" json:"uv,omitempty"`                              // Índice UV
+	Text      string       `protobuf:"bytes,6,opt,name=text,proto3" json:"text,omitempty"`                            // Descrição da condição do tempo (ex: "Partly cloudy")
+}
+
+func (x *Conditions) Reset() {
+	*x = Conditions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Conditions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conditions) ProtoMessage() {}
+
+func (x *Conditions) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conditions.ProtoReflect.Descriptor instead.
+func (*Conditions) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Conditions) GetFeelsLike() *Temperature {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return nil
+}
+
+func (x *Conditions) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *Conditions) GetWind() *Wind {
+	if x != nil {
+		return x.Wind
+	}
+	return nil
+}
+
+func (x *Conditions) GetPressure() *Pressure {
+	if x != nil {
+		return x.Pressure
+	}
+	return nil
+}
+
+func (x *Conditions) GetUv() float64 {
+	if x != nil {
+		return x.Uv
+	}
+	return 0
+}
+
+func (x *Conditions) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Temperature é uma temperatura nas três escalas
+type Temperature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TempC float64 `protobuf:"fixed64,1,opt,name=temp_c,json=tempC,proto3" json:"temp_c,omitempty"`
+	TempF float64 `protobuf:"fixed64,2,opt,name=temp_f,json=tempF,proto3" json:"temp_f,omitempty"`
+	TempK float64 `protobuf:"fixed64,3,opt,name=temp_k,json=tempK,proto3" json:"temp_k,omitempty"`
+}
+
+func (x *Temperature) Reset() {
+	*x = Temperature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Temperature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Temperature) ProtoMessage() {}
+
+func (x *Temperature) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Temperature.ProtoReflect.Descriptor instead.
+func (*Temperature) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Temperature) GetTempC() float64 {
+	if x != nil {
+		return x.TempC
+	}
+	return 0
+}
+
+func (x *Temperature) GetTempF() float64 {
+	if x != nil {
+		return x.TempF
+	}
+	return 0
+}
+
+func (x *Temperature) GetTempK() float64 {
+	if x != nil {
+		return x.TempK
+	}
+	return 0
+}
+
+// Wind representa a velocidade e a direção do vento
+type Wind struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kph       float64 `protobuf:"fixed64,1,opt,name=kph,proto3" json:"kph,omitempty"`           // Velocidade em km/h
+	MS        float64 `protobuf:"fixed64,2,opt,name=m_s,json=mS,proto3" json:"m_s,omitempty"`   // Velocidade em m/s
+	Mph       float64 `protobuf:"fixed64,3,opt,name=mph,proto3" json:"mph,omitempty"`           // Velocidade em milhas por hora
+	Direction string  `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"` // Direção (ex: "NE"), vazia quando desconhecida
+}
+
+func (x *Wind) Reset() {
+	*x = Wind{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Wind) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Wind) ProtoMessage() {}
+
+func (x *Wind) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Wind.ProtoReflect.Descriptor instead.
+func (*Wind) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Wind) GetKph() float64 {
+	if x != nil {
+		return x.Kph
+	}
+	return 0
+}
+
+func (x *Wind) GetMS() float64 {
+	if x != nil {
+		return x.MS
+	}
+	return 0
+}
+
+func (x *Wind) GetMph() float64 {
+	if x != nil {
+		return x.Mph
+	}
+	return 0
+}
+
+func (x *Wind) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+// Pressure representa a pressão atmosférica
+type Pressure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hpa  float64 `protobuf:"fixed64,1,opt,name=hpa,proto3" json:"hpa,omitempty"`               // Hectopascals (equivalente a milibares)
+	InHg float64 `protobuf:"fixed64,2,opt,name=in_hg,json=inHg,proto3" json:"in_hg,omitempty"` // Polegadas de mercúrio
+}
+
+func (x *Pressure) Reset() {
+	*x = Pressure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pressure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pressure) ProtoMessage() {}
+
+func (x *Pressure) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pressure.ProtoReflect.Descriptor instead.
+func (*Pressure) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Pressure) GetHpa() float64 {
+	if x != nil {
+		return x.Hpa
+	}
+	return 0
+}
+
+func (x *Pressure) GetInHg() float64 {
+	if x != nil {
+		return x.InHg
+	}
+	return 0
+}
+
+// BatchGetWeatherRequest é a consulta de clima de um lote de CEPs
+type BatchGetWeatherRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ceps []string `protobuf:"bytes,1,rep,name=ceps,proto3" json:"ceps,omitempty"`
+}
+
+func (x *BatchGetWeatherRequest) Reset() {
+	*x = BatchGetWeatherRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetWeatherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetWeatherRequest) ProtoMessage() {}
+
+func (x *BatchGetWeatherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetWeatherRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetWeatherRequest) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchGetWeatherRequest) GetCeps() []string {
+	if x != nil {
+		return x.Ceps
+	}
+	return nil
+}
+
+// BatchGetWeatherResponse traz um resultado por CEP, na ordem da requisição
+type BatchGetWeatherResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*BatchItem `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchGetWeatherResponse) Reset() {
+	*x = BatchGetWeatherResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetWeatherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetWeatherResponse) ProtoMessage() {}
+
+func (x *BatchGetWeatherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetWeatherResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetWeatherResponse) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchGetWeatherResponse) GetResults() []*BatchItem {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchItem é o resultado de um CEP do lote: o clima ou o erro
+type BatchItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cep string `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"` // CEP como informado pelo cliente
+	// Types that are assignable to Result:
+	//	*BatchItem_Weather
+	//	*BatchItem_Error
+	Result isBatchItem_Result `protobuf_oneof:"result"`
+}
+
+func (x *BatchItem) Reset() {
+	*x = BatchItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItem) ProtoMessage() {}
+
+func (x *BatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItem.ProtoReflect.Descriptor instead.
+func (*BatchItem) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchItem) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (m *BatchItem) GetResult() isBatchItem_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *BatchItem) GetWeather() *Weather {
+	if x, ok := x.GetResult().(*BatchItem_Weather); ok {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *BatchItem) GetError() *Problem {
+	if x, ok := x.GetResult().(*BatchItem_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isBatchItem_Result interface {
+	isBatchItem_Result()
+}
+
+type BatchItem_Weather struct {
+	Weather *Weather `protobuf:"bytes,2,opt,name=weather,proto3,oneof"`
+}
+
+type BatchItem_Error struct {
+	Error *Problem `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchItem_Weather) isBatchItem_Result() {}
+
+func (*BatchItem_Error) isBatchItem_Result() {}
+
+// Problem é um erro no formato da RFC 7807, como nas respostas HTTP
+type Problem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type     string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Title    string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Status   int32  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Detail   string `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	Instance string `protobuf:"bytes,5,opt,name=instance,proto3" json:"instance,omitempty"`
+	Code     string `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
+	TraceId  string `protobuf:"bytes,7,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+}
+
+func (x *Problem) Reset() {
+	*x = Problem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Problem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Problem) ProtoMessage() {}
+
+func (x *Problem) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Problem.ProtoReflect.Descriptor instead.
+func (*Problem) Descriptor() ([]byte, []int) {
+	return file_weather_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Problem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Problem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Problem) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Problem) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *Problem) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *Problem) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Problem) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+var File_weather_proto protoreflect.FileDescriptor
+
+var file_weather_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0d, 0x63, 0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x22, 0x41,
+	0x0a, 0x11, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x65, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x63, 0x65, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65,
+	0x64, 0x22, 0x9d, 0x01, 0x0a, 0x07, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74,
+	0x79, 0x12, 0x15, 0x0a, 0x06, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x05, 0x74, 0x65, 0x6d, 0x70, 0x43, 0x12, 0x15, 0x0a, 0x06, 0x74, 0x65, 0x6d, 0x70,
+	0x5f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x65, 0x6d, 0x70, 0x46, 0x12,
+	0x15, 0x0a, 0x06, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x74, 0x65, 0x6d, 0x70, 0x4b, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x65, 0x70,
+	0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0xe5, 0x01, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x39, 0x0a, 0x0a, 0x66, 0x65, 0x65, 0x6c, 0x73, 0x5f, 0x6c, 0x69, 0x6b, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x52, 0x09, 0x66, 0x65, 0x65, 0x6c, 0x73, 0x4c, 0x69, 0x6b, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68,
+	0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x68,
+	0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x12, 0x27, 0x0a, 0x04, 0x77, 0x69, 0x6e, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x77, 0x69, 0x6e, 0x64,
+	0x12, 0x33, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x52, 0x08, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x75, 0x72, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x75, 0x76, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x02, 0x75, 0x76, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x52, 0x0a, 0x0b, 0x54, 0x65, 0x6d,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x74, 0x65, 0x6d, 0x70,
+	0x5f, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x65, 0x6d, 0x70, 0x43, 0x12,
+	0x15, 0x0a, 0x06, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x74, 0x65, 0x6d, 0x70, 0x46, 0x12, 0x15, 0x0a, 0x06, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6b,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x74, 0x65, 0x6d, 0x70, 0x4b, 0x22, 0x59, 0x0a,
+	0x04, 0x57, 0x69, 0x6e, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x70, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x03, 0x6b, 0x70, 0x68, 0x12, 0x0f, 0x0a, 0x03, 0x6d, 0x5f, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x6d, 0x53, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x70, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x70, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x31, 0x0a, 0x08, 0x50, 0x72, 0x65, 0x73,
+	0x73, 0x75, 0x72, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x68, 0x70, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x68, 0x70, 0x61, 0x12, 0x13, 0x0a, 0x05, 0x69, 0x6e, 0x5f, 0x68, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x69, 0x6e, 0x48, 0x67, 0x22, 0x2c, 0x0a, 0x16, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x65, 0x70, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x63, 0x65, 0x70, 0x73, 0x22, 0x4d, 0x0a, 0x17, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x8b, 0x01, 0x0a, 0x09, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x65, 0x70, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x65, 0x70, 0x12, 0x32, 0x0a, 0x07, 0x77, 0x65, 0x61, 0x74,
+	0x68, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x65, 0x70, 0x77,
+	0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65,
+	0x72, 0x48, 0x00, 0x52, 0x07, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x65,
+	0x70, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x62,
+	0x6c, 0x65, 0x6d, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0xae, 0x01, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x62, 0x6c,
+	0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x32, 0xba, 0x01, 0x0a, 0x0e, 0x57, 0x65, 0x61, 0x74,
+	0x68, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x63, 0x65, 0x70, 0x77, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74,
+	0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x63, 0x65, 0x70,
+	0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68,
+	0x65, 0x72, 0x12, 0x60, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x57, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x25, 0x2e, 0x63, 0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x57, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63,
+	0x65, 0x70, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x24, 0x5a, 0x22, 0x63, 0x65, 0x70, 0x2d, 0x77, 0x65, 0x61, 0x74,
+	0x68, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_weather_proto_rawDescOnce sync.Once
+	file_weather_proto_rawDescData = file_weather_proto_rawDesc
+)
+
+func file_weather_proto_rawDescGZIP() []byte {
+	file_weather_proto_rawDescOnce.Do(func() {
+		file_weather_proto_rawDescData = protoimpl.X.CompressGZIP(file_weather_proto_rawDescData)
+	})
+	return file_weather_proto_rawDescData
+}
+
+var file_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_weather_proto_goTypes = []interface{}{
+	(*GetWeatherRequest)(nil),       // 0: cepweather.v1.GetWeatherRequest
+	(*Weather)(nil),                 // 1: cepweather.v1.Weather
+	(*Conditions)(nil),              // 2: cepweather.v1.Conditions
+	(*Temperature)(nil),             // 3: cepweather.v1.Temperature
+	(*Wind)(nil),                    // 4: cepweather.v1.Wind
+	(*Pressure)(nil),                // 5: cepweather.v1.Pressure
+	(*BatchGetWeatherRequest)(nil),  // 6: cepweather.v1.BatchGetWeatherRequest
+	(*BatchGetWeatherResponse)(nil), // 7: cepweather.v1.BatchGetWeatherResponse
+	(*BatchItem)(nil),               // 8: cepweather.v1.BatchItem
+	(*Problem)(nil),                 // 9: cepweather.v1.Problem
+}
+var file_weather_proto_depIdxs = []int32{
+	2, // 0: cepweather.v1.Weather.conditions:type_name -> cepweather.v1.Conditions
+	3, // 1: cepweather.v1.Conditions.feels_like:type_name -> cepweather.v1.Temperature
+	4, // 2: cepweather.v1.Conditions.wind:type_name -> cepweather.v1.Wind
+	5, // 3: cepweather.v1.Conditions.pressure:type_name -> cepweather.v1.Pressure
+	8, // 4: cepweather.v1.BatchGetWeatherResponse.results:type_name -> cepweather.v1.BatchItem
+	1, // 5: cepweather.v1.BatchItem.weather:type_name -> cepweather.v1.Weather
+	9, // 6: cepweather.v1.BatchItem.error:type_name -> cepweather.v1.Problem
+	0, // 7: cepweather.v1.WeatherService.GetWeather:input_type -> cepweather.v1.GetWeatherRequest
+	6, // 8: cepweather.v1.WeatherService.BatchGetWeather:input_type -> cepweather.v1.BatchGetWeatherRequest
+	1, // 9: cepweather.v1.WeatherService.GetWeather:output_type -> cepweather.v1.Weather
+	7, // 10: cepweather.v1.WeatherService.BatchGetWeather:output_type -> cepweather.v1.BatchGetWeatherResponse
+	9, // [9:11] is the sub-list for method output_type
+	7, // [7:9] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_weather_proto_init() }
+func file_weather_proto_init() {
+	if File_weather_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_weather_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWeatherRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Weather); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Conditions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Temperature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Wind); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pressure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetWeatherRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetWeatherResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Problem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_weather_proto_msgTypes[8].OneofWrappers = []interface{}{
+		(*BatchItem_Weather)(nil),
+		(*BatchItem_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_weather_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_weather_proto_goTypes,
+		DependencyIndexes: file_weather_proto_depIdxs,
+		MessageInfos:      file_weather_proto_msgTypes,
+	}.Build()
+	File_weather_proto = out.File
+	file_weather_proto_rawDesc = nil
+	file_weather_proto_goTypes = nil
+	file_weather_proto_depIdxs = nil
+}
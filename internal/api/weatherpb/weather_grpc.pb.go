@@ -0,0 +1,165 @@
+// Contrato gRPC entre o Serviço A e o Serviço B
+// Espelha os tipos JSON do pacote api (WeatherRequest, WeatherResponse,
+// BatchRequest e BatchResponse), para os chamadores internos que preferem gRPC
+//
+// Para regenerar o código Go após alterar este arquivo:
+//   go generate ./internal/api/weatherpb
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: weather.proto
+
+package weatherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	WeatherService_GetWeather_FullMethodName      = "/cepweather.v1.WeatherService/GetWeather"
+	WeatherService_BatchGetWeather_FullMethodName = "/cepweather.v1.WeatherService/BatchGetWeather"
+)
+
+// WeatherServiceClient is the client API for WeatherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WeatherServiceClient interface {
+	// GetWeather consulta o clima de um CEP
+	// Erros: INVALID_ARGUMENT (CEP inválido), NOT_FOUND (CEP não encontrado),
+	// INTERNAL (falha nas APIs externas)
+	GetWeather(ctx context.Context, in *GetWeatherRequest, opts ...grpc.CallOption) (*Weather, error)
+	// BatchGetWeather consulta o clima de até 500 CEPs de uma vez
+	// Falhas individuais aparecem no item correspondente; a chamada só falha
+	// (INVALID_ARGUMENT) quando o lote está vazio ou excede o limite
+	BatchGetWeather(ctx context.Context, in *BatchGetWeatherRequest, opts ...grpc.CallOption) (*BatchGetWeatherResponse, error)
+}
+
+type weatherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWeatherServiceClient(cc grpc.ClientConnInterface) WeatherServiceClient {
+	return &weatherServiceClient{cc}
+}
+
+func (c *weatherServiceClient) GetWeather(ctx context.Context, in *GetWeatherRequest, opts ...grpc.CallOption) (*Weather, error) {
+	out := new(Weather)
+	err := c.cc.Invoke(ctx, WeatherService_GetWeather_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) BatchGetWeather(ctx context.Context, in *BatchGetWeatherRequest, opts ...grpc.CallOption) (*BatchGetWeatherResponse, error) {
+	out := new(BatchGetWeatherResponse)
+	err := c.cc.Invoke(ctx, WeatherService_BatchGetWeather_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WeatherServiceServer is the server API for WeatherService service.
+// All implementations must embed UnimplementedWeatherServiceServer
+// for forward compatibility
+type WeatherServiceServer interface {
+	// GetWeather consulta o clima de um CEP
+	// Erros: INVALID_ARGUMENT (CEP inválido), NOT_FOUND (CEP não encontrado),
+	// INTERNAL (falha nas APIs externas)
+	GetWeather(context.Context, *GetWeatherRequest) (*Weather, error)
+	// BatchGetWeather consulta o clima de até 500 CEPs de uma vez
+	// Falhas individuais aparecem no item correspondente; a chamada só falha
+	// (INVALID_ARGUMENT) quando o lote está vazio ou excede o limite
+	BatchGetWeather(context.Context, *BatchGetWeatherRequest) (*BatchGetWeatherResponse, error)
+	mustEmbedUnimplementedWeatherServiceServer()
+}
+
+// UnimplementedWeatherServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedWeatherServiceServer struct {
+}
+
+func (UnimplementedWeatherServiceServer) GetWeather(context.Context, *GetWeatherRequest) (*Weather, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWeather not implemented")
+}
+func (UnimplementedWeatherServiceServer) BatchGetWeather(context.Context, *BatchGetWeatherRequest) (*BatchGetWeatherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetWeather not implemented")
+}
+func (UnimplementedWeatherServiceServer) mustEmbedUnimplementedWeatherServiceServer() {}
+
+// UnsafeWeatherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WeatherServiceServer will
+// result in compilation errors.
+type UnsafeWeatherServiceServer interface {
+	mustEmbedUnimplementedWeatherServiceServer()
+}
+
+func RegisterWeatherServiceServer(s grpc.ServiceRegistrar, srv WeatherServiceServer) {
+	s.RegisterService(&WeatherService_ServiceDesc, srv)
+}
+
+func _WeatherService_GetWeather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWeatherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetWeather(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetWeather_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetWeather(ctx, req.(*GetWeatherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_BatchGetWeather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetWeatherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).BatchGetWeather(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_BatchGetWeather_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).BatchGetWeather(ctx, req.(*BatchGetWeatherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WeatherService_ServiceDesc is the grpc.ServiceDesc for WeatherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WeatherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cepweather.v1.WeatherService",
+	HandlerType: (*WeatherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWeather",
+			Handler:    _WeatherService_GetWeather_Handler,
+		},
+		{
+			MethodName: "BatchGetWeather",
+			Handler:    _WeatherService_BatchGetWeather_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "weather.proto",
+}
@@ -0,0 +1,213 @@
+// Pacote auth identifica os clientes do Serviço A pela chave de API ou pelo
+// token de acesso (JWT) emitido pelo provedor de identidade (ver Verifier)
+//
+// As chaves ficam em um arquivo (KeyStore) com os metadados de cada cliente:
+// nome, organização (tenant), plano (tier) das cotas de requisições e rotas
+// permitidas. O arquivo
+// pode ser alterado com o serviço em execução (ver KeyStore.Watch): clientes
+// são incluídos, removidos ou têm as chaves trocadas sem reinício.
+//
+// Exemplo (YAML; JSON também é aceito):
+//
+//	keys:
+//	  - client: acme
+//	    key: chave-da-acme
+//	    tenant: acme-corp
+//	    tier: premium
+//	  - client: parceiro-x
+//	    key_sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	    endpoints: ["/weather/{cep}"]
+package auth
+
+import (
+	"bytes"
+	"cep-weather/internal/config"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formas de autenticação do cliente (Client.Method)
+const (
+	MethodAPIKey = "api_key" // Chave de API do arquivo de chaves (KeyStore)
+	MethodJWT    = "jwt"     // Token de acesso do provedor de identidade (Verifier)
+)
+
+// Client descreve o cliente autenticado
+type Client struct {
+	Name      string   // Identificador do cliente (ex: "acme")
+	Tenant    string   // Organização do cliente (ex: "acme-corp"), quando informada
+	Tier      string   // Plano das cotas de requisições ("" usa o plano padrão das chaves)
+	Endpoints []string // Rotas permitidas (ex: "/weather/{cep}"); vazio permite todas
+	KeyID     string   // Identificador da credencial: da chave de API ou do emissor e sujeito do token (ver KeyID)
+	Method    string   // Forma de autenticação (MethodAPIKey ou MethodJWT)
+}
+
+// Allows informa se o cliente pode acessar a rota
+func (c Client) Allows(route string) bool {
+	if len(c.Endpoints) == 0 {
+		return true
+	}
+	for _, e := range c.Endpoints {
+		if e == route {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyID retorna o identificador de uma chave de API: o início do seu hash SHA-256
+// Permite distinguir as chaves em logs, spans e cotas sem expô-las
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
+// entry é uma chave do arquivo, informada em texto (key) ou pelo hash (key_sha256)
+type entry struct {
+	Client    string   `yaml:"client"`
+	Tenant    string   `yaml:"tenant"`
+	Key       string   `yaml:"key"`
+	KeySHA256 string   `yaml:"key_sha256"`
+	Tier      string   `yaml:"tier"`
+	Endpoints []string `yaml:"endpoints"`
+}
+
+// keyFile é o formato do arquivo de chaves
+type keyFile struct {
+	Keys []entry `yaml:"keys"`
+}
+
+// clientName restringe o nome do cliente e da organização a caracteres
+// seguros para logs e baggage
+var clientName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// sha256Hex reconhece um hash SHA-256 em hexadecimal
+var sha256Hex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// KeyStore guarda as chaves de API válidas, indexadas pelo hash SHA-256
+// A troca do conteúdo na recarga é atômica; o KeyStore é seguro para uso concorrente
+type KeyStore struct {
+	path    string
+	clients atomic.Pointer[map[string]Client]
+}
+
+// NewKeyStore carrega o arquivo de chaves
+// Retorna erro descrevendo todas as entradas inválidas encontradas
+func NewKeyStore(path string) (*KeyStore, error) {
+	s := &KeyStore{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload lê novamente o arquivo de chaves
+// Um arquivo inválido é rejeitado por inteiro, mantendo as chaves atuais
+func (s *KeyStore) Reload() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("keys file %s: %w", s.path, err)
+	}
+	var f keyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("keys file %s: %w", s.path, err)
+	}
+
+	clients := make(map[string]Client, len(f.Keys))
+	var errs []error
+	for i, e := range f.Keys {
+		hash, err := e.hash()
+		if err == nil && !clientName.MatchString(e.Client) {
+			err = fmt.Errorf("client must be a non-empty identifier ([A-Za-z0-9._-]), got %q", e.Client)
+		}
+		if err == nil && e.Tenant != "" && !clientName.MatchString(e.Tenant) {
+			err = fmt.Errorf("tenant must be an identifier ([A-Za-z0-9._-]), got %q", e.Tenant)
+		}
+		if err == nil {
+			if _, dup := clients[hash]; dup {
+				err = errors.New("duplicate key")
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("keys[%d]: %w", i, err))
+			continue
+		}
+		clients[hash] = Client{Name: e.Client, Tenant: e.Tenant, Tier: e.Tier, Endpoints: e.Endpoints, KeyID: hash[:12], Method: MethodAPIKey}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("keys file %s: %w", s.path, err)
+	}
+	s.clients.Store(&clients)
+	return nil
+}
+
+// hash retorna o hash SHA-256 da chave da entrada, em hexadecimal
+func (e entry) hash() (string, error) {
+	switch {
+	case (e.Key == "") == (e.KeySHA256 == ""):
+		return "", errors.New("exactly one of key or key_sha256 is required")
+	case e.Key != "":
+		sum := sha256.Sum256([]byte(e.Key))
+		return hex.EncodeToString(sum[:]), nil
+	case !sha256Hex.MatchString(e.KeySHA256):
+		return "", fmt.Errorf("key_sha256 must be 64 hexadecimal characters")
+	default:
+		return strings.ToLower(e.KeySHA256), nil
+	}
+}
+
+// Lookup retorna o cliente dono da chave de API
+func (s *KeyStore) Lookup(key string) (Client, bool) {
+	if key == "" {
+		return Client{}, false
+	}
+	sum := sha256.Sum256([]byte(key))
+	c, ok := (*s.clients.Load())[hex.EncodeToString(sum[:])]
+	return c, ok
+}
+
+// Len retorna a quantidade de chaves carregadas
+func (s *KeyStore) Len() int {
+	return len(*s.clients.Load())
+}
+
+// Watch recarrega o arquivo de chaves sempre que ele for alterado, até que ctx
+// seja cancelado (ver config.Watch)
+// Falhas na recarga são registradas no log e mantêm as chaves atuais
+func (s *KeyStore) Watch(ctx context.Context, interval time.Duration) {
+	config.Watch(ctx, s.path, interval, func() {
+		if err := s.Reload(); err != nil {
+			log.Printf("Arquivo de chaves inválido; mantendo as chaves atuais: %v", err)
+			return
+		}
+		log.Printf("Arquivo de chaves recarregado: %d chaves", s.Len())
+	})
+}
+
+// clientKey é a chave do cliente autenticado no contexto
+type clientKey struct{}
+
+// WithClient retorna um contexto com o cliente autenticado
+func WithClient(ctx context.Context, c Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromContext retorna o cliente autenticado da requisição, se houver
+func FromContext(ctx context.Context) (Client, bool) {
+	c, ok := ctx.Value(clientKey{}).(Client)
+	return c, ok
+}
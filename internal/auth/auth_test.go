@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeKeys grava o arquivo de chaves em um diretório temporário
+// writeKeys substitui o arquivo de chaves de uma vez (arquivo temporário e
+// rename), para que Watch nunca leia um arquivo escrito pela metade
+func writeKeys(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyStore_Lookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	// key_sha256 de "segredo-x"
+	writeKeys(t, path, `
+keys:
+  - client: acme
+    key: chave-da-acme
+    tenant: acme-corp
+    tier: premium
+  - client: parceiro-x
+    key_sha256: `+strings.ToUpper("2fce05248bcf9a0cfe9ffe72db0715ea4542478508843045367a502cca59c5cd")+`
+    endpoints: ["/weather/{cep}"]
+`)
+	s, err := NewKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, ok := s.Lookup("chave-da-acme")
+	if !ok || c.Name != "acme" || c.Tenant != "acme-corp" || c.Tier != "premium" || c.KeyID != KeyID("chave-da-acme") {
+		t.Fatalf("unexpected client: %+v (found %v)", c, ok)
+	}
+	if !c.Allows("/weather") || !c.Allows("/weather/{cep}") {
+		t.Fatalf("expected client without endpoints to access every route")
+	}
+	if c, ok := s.Lookup("segredo-x"); !ok || c.Name != "parceiro-x" || c.Allows("/weather") {
+		t.Fatalf("expected client from key_sha256 restricted to /weather/{cep}, got %+v", c)
+	}
+	for _, key := range []string{"", "outra-chave", "CHAVE-DA-ACME"} {
+		if _, ok := s.Lookup(key); ok {
+			t.Fatalf("expected key %q to be rejected", key)
+		}
+	}
+	if s.Len() != 2 {
+		t.Fatalf("expected 2 keys, got %d", s.Len())
+	}
+
+	restricted := Client{Endpoints: []string{"/weather/{cep}"}}
+	if !restricted.Allows("/weather/{cep}") || restricted.Allows("/weather") {
+		t.Fatalf("expected access only to the listed endpoints")
+	}
+}
+
+func TestKeyStore_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"both key forms":    "keys: [{client: a, key: x, key_sha256: " + strings.Repeat("0", 64) + "}]",
+		"no key":            "keys: [{client: a}]",
+		"bad hash":          "keys: [{client: a, key_sha256: abc}]",
+		"no client":         "keys: [{key: x}]",
+		"client with space": "keys: [{client: Acme Corp, key: x}]",
+		"tenant with space": "keys: [{client: a, tenant: Acme Corp, key: x}]",
+		"duplicate key":     "keys: [{client: a, key: x}, {client: b, key: x}]",
+		"unknown field":     "keys: [{client: a, key: x, plan: gold}]",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.yaml")
+			writeKeys(t, path, content)
+			if _, err := NewKeyStore(path); err == nil {
+				t.Fatalf("expected error for %s", content)
+			}
+		})
+	}
+	if _, err := NewKeyStore(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatalf("expected error for a missing file")
+	}
+}
+
+func TestKeyStore_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	writeKeys(t, path, `{"keys": [{"client": "acme", "key": "k1"}]}`)
+	s, err := NewKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // Watch registra a versão inicial do arquivo
+
+	waitFor := func(cond func() bool) bool {
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if cond() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Troca da chave: a anterior deixa de valer sem reinício
+	writeKeys(t, path, `{"keys": [{"client": "acme", "key": "k2-rotated"}]}`)
+	if !waitFor(func() bool { _, ok := s.Lookup("k2-rotated"); return ok }) {
+		t.Fatalf("expected rotated key to be loaded")
+	}
+	if _, ok := s.Lookup("k1"); ok {
+		t.Fatalf("expected old key to be revoked")
+	}
+
+	// Um arquivo inválido mantém as chaves atuais
+	writeKeys(t, path, `{"keys": [{"client": "acme"}], "extra": true}`)
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := s.Lookup("k2-rotated"); !ok {
+		t.Fatalf("expected current keys to survive an invalid file")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatalf("expected no client in an empty context")
+	}
+	ctx := WithClient(context.Background(), Client{Name: "acme"})
+	if c, ok := FromContext(ctx); !ok || c.Name != "acme" {
+		t.Fatalf("expected client from context, got %+v", c)
+	}
+}
@@ -0,0 +1,298 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// jwk é uma chave pública do JWKS (RFC 7517), já convertida para crypto
+type jwk struct {
+	alg string // Algoritmo declarado na chave ("" aceita o algoritmo compatível com o tipo)
+	key any    // *rsa.PublicKey ou *ecdsa.PublicKey
+}
+
+// rawJWK é uma chave do documento JWKS, como publicada pelo provedor de identidade
+type rawJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`   // RSA: módulo
+	E   string `json:"e"`   // RSA: expoente
+	Crv string `json:"crv"` // EC: curva
+	X   string `json:"x"`   // EC: coordenada x
+	Y   string `json:"y"`   // EC: coordenada y
+}
+
+// KeySet guarda as chaves públicas de assinatura do provedor de identidade
+// (JWKS), obtidas de um arquivo ou de uma URL
+//
+// As chaves ficam em cache por ttl. Um token assinado com uma chave
+// desconhecida (kid) antecipa a atualização, para acompanhar a rotação de
+// chaves do provedor; essas atualizações antecipadas respeitam um intervalo
+// mínimo, para que tokens forjados não sobrecarreguem o provedor. Falhas na
+// atualização mantêm as chaves atuais.
+//
+// A consulta ao provedor é feita fora do mutex, uma de cada vez: enquanto ela
+// não termina, os tokens com chaves já conhecidas continuam sendo verificados
+// com o cache, e apenas os que dependem da atualização (kid desconhecido ou
+// cache vazio) aguardam por ela. A consulta não é interrompida quando a
+// requisição que a iniciou é cancelada, pois seu resultado serve às demais.
+//
+// O KeySet é seguro para uso concorrente.
+type KeySet struct {
+	source string // Caminho do arquivo ou URL (http/https) do JWKS
+	ttl    time.Duration
+	client *http.Client
+
+	mu         sync.Mutex
+	keys       map[string]jwk   // Chaves pelo kid
+	fetched    time.Time        // Última atualização bem-sucedida
+	tried      time.Time        // Última tentativa de atualização
+	err        error            // Erro da última tentativa de atualização
+	refreshing <-chan struct{}  // Fechado ao fim da atualização em andamento (nil sem atualização)
+	now        func() time.Time // Relógio, substituível em testes
+}
+
+// minRefresh é o intervalo mínimo entre as atualizações antecipadas do JWKS
+const minRefresh = 10 * time.Second
+
+// NewKeySet cria o cache do JWKS
+//
+// Parâmetros:
+//   - source: Caminho do arquivo ou URL (http:// ou https://) do JWKS
+//   - ttl: Validade do cache (ex: 10m)
+func NewKeySet(source string, ttl time.Duration) *KeySet {
+	return &KeySet{
+		source: source,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		now:    time.Now,
+	}
+}
+
+// Load carrega as chaves imediatamente
+// Usado na inicialização, para que um JWKS inacessível ou inválido seja
+// detectado antes de receber requisições
+func (s *KeySet) Load(ctx context.Context) error {
+	s.mu.Lock()
+	done := s.refresh(ctx)
+	s.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// lookup retorna a chave pelo kid, atualizando o cache quando necessário
+// Um kid vazio é aceito apenas quando o JWKS tem uma única chave
+//
+// Com a chave no cache, a atualização (se necessária) segue em segundo plano;
+// sem ela, lookup aguarda a atualização ou o cancelamento de ctx.
+func (s *KeySet) lookup(ctx context.Context, kid string) (jwk, error) {
+	s.mu.Lock()
+	now := s.now()
+	k, ok := s.find(kid)
+	stale := s.keys == nil || now.Sub(s.fetched) >= s.ttl
+	done := s.refreshing
+	if (stale || !ok) && done == nil && now.Sub(s.tried) >= minRefresh {
+		done = s.refresh(ctx)
+	}
+	s.mu.Unlock()
+
+	if ok || done == nil {
+		if !ok {
+			return jwk{}, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+		}
+		return k, nil
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return jwk{}, ctx.Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		return jwk{}, s.err
+	}
+	if k, ok = s.find(kid); !ok {
+		return jwk{}, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+	return k, nil
+}
+
+// find procura a chave no cache
+func (s *KeySet) find(kid string) (jwk, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// refresh inicia a atualização das chaves, se nenhuma estiver em andamento,
+// e retorna o canal fechado ao fim dela
+//
+// Deve ser chamado com s.mu travado. O JWKS é lido em uma goroutine, sem o
+// mutex, com um contexto que mantém o trace de ctx mas não o cancelamento.
+func (s *KeySet) refresh(ctx context.Context) <-chan struct{} {
+	if s.refreshing != nil {
+		return s.refreshing
+	}
+	done := make(chan struct{})
+	s.refreshing = done
+	tried := s.now()
+	s.tried = tried
+
+	go func() {
+		defer close(done)
+		keys, err := s.fetch(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.refreshing, s.err = nil, err
+		switch {
+		case err == nil:
+			s.keys, s.fetched = keys, tried
+		case s.keys != nil:
+			log.Printf("Erro ao atualizar o JWKS; mantendo as chaves atuais: %v", err)
+		}
+	}()
+	return done
+}
+
+// fetch lê e converte o JWKS da origem
+func (s *KeySet) fetch(ctx context.Context) (map[string]jwk, error) {
+	data, err := s.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("jwks %s: %w", s.source, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwks %s: %w", s.source, err)
+	}
+	return keys, nil
+}
+
+// read obtém o documento JWKS do arquivo ou da URL
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// parseJWKS converte as chaves de assinatura RSA e EC (P-256) do documento
+// Chaves de outros tipos ou de cifragem (use "enc") são ignoradas
+func parseJWKS(data []byte) (map[string]jwk, error) {
+	var doc struct {
+		Keys []rawJWK `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]jwk, len(doc.Keys))
+	for i, raw := range doc.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := raw.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("keys[%d]: %w", i, err)
+		}
+		if key != nil {
+			keys[raw.Kid] = jwk{alg: raw.Alg, key: key}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA or P-256 signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey converte a chave; retorna nil para tipos não suportados
+func (k rawJWK) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err1 := decodeInt(k.N)
+		e, err2 := decodeInt(k.E)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || n.BitLen() < 2048 {
+			return nil, fmt.Errorf("unsupported RSA key (%d bits)", n.BitLen())
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err1 := decodeInt(k.X)
+		y, err2 := decodeInt(k.Y)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, err
+		}
+		// A validação do ponto é feita por crypto/ecdh: 0x04 || x || y
+		point := make([]byte, 65)
+		point[0] = 4
+		if x.BitLen() > 256 || y.BitLen() > 256 {
+			return nil, errors.New("EC point is not on the P-256 curve")
+		}
+		x.FillBytes(point[1:33])
+		y.FillBytes(point[33:])
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, errors.New("EC point is not on the P-256 curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// decodeInt decodifica um inteiro em base64url sem preenchimento (RFC 7518)
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer %q", s)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
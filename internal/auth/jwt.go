@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Erros da validação dos tokens (JWT)
+var (
+	// ErrInvalidToken indica token malformado, com assinatura inválida, expirado
+	// ou emitido para outro emissor ou audiência
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrInsufficientScope indica token válido cujos escopos não dão acesso a
+	// nenhum endpoint
+	ErrInsufficientScope = errors.New("insufficient scope")
+)
+
+// Claims reúne as informações do token usadas pelo Serviço A
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	ClientID  string   `json:"client_id"` // Cliente OAuth 2.0 (RFC 9068)
+	AZP       string   `json:"azp"`       // Cliente OIDC ("authorized party")
+	Scope     string   `json:"scope"`     // Escopos separados por espaço
+	Scp       []string `json:"scp"`       // Escopos em lista (usado por alguns provedores)
+	Tenant    string   `json:"tenant"`    // Organização do cliente (claim privada, opcional)
+}
+
+// Scopes retorna os escopos concedidos pelo token
+func (c Claims) Scopes() []string {
+	return append(strings.Fields(c.Scope), c.Scp...)
+}
+
+// audience aceita a audiência como texto ou como lista (RFC 7519, seção 4.1.3)
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]string)(a))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*a = audience{s}
+	return nil
+}
+
+// Verifier valida os tokens de acesso (JWT) emitidos pelo provedor de
+// identidade e identifica o cliente
+//
+// São aceitas apenas as assinaturas RS256 e ES256, verificadas com as chaves
+// do JWKS; o emissor (iss) e a audiência (aud) devem ser os configurados e o
+// token deve estar no prazo de validade (exp obrigatório, nbf opcional),
+// com a tolerância leeway para diferenças de relógio. Os escopos do token
+// definem os endpoints permitidos ao cliente.
+type Verifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+	scopes   map[string][]string // Endpoints liberados por escopo
+	tier     string              // Plano das cotas dos clientes com token
+	now      func() time.Time    // Relógio, substituível em testes
+}
+
+// NewVerifier cria o validador dos tokens
+//
+// Parâmetros:
+//   - keys: Chaves públicas do provedor de identidade (JWKS)
+//   - issuer: Emissor esperado (claim iss)
+//   - audience: Audiência esperada (claim aud), normalmente o identificador do Serviço A
+//   - leeway: Tolerância para diferenças de relógio na validade do token
+//   - scopes: Endpoints liberados por cada escopo (ex: "weather:read" → ["/weather/{cep}"])
+//   - tier: Plano das cotas de requisições dos clientes com token ("" usa o plano padrão)
+func NewVerifier(keys *KeySet, issuer, audience string, leeway time.Duration, scopes map[string][]string, tier string) *Verifier {
+	return &Verifier{keys: keys, issuer: issuer, audience: audience, leeway: leeway, scopes: scopes, tier: tier, now: time.Now}
+}
+
+// Verify valida o token e retorna o cliente identificado por ele
+//
+// O nome do cliente vem de client_id, azp ou sub, nessa ordem, a organização
+// da claim tenant e os endpoints permitidos são os dos escopos concedidos.
+//
+// Retorna ErrInvalidToken (com o motivo) quando o token não é aceito e
+// ErrInsufficientScope quando nenhum escopo dá acesso a um endpoint
+func (v *Verifier) Verify(ctx context.Context, token string) (Client, error) {
+	claims, err := v.verify(ctx, token)
+	if err != nil {
+		return Client{}, err
+	}
+
+	var endpoints []string
+	for _, scope := range claims.Scopes() {
+		for _, e := range v.scopes[scope] {
+			if !slices.Contains(endpoints, e) {
+				endpoints = append(endpoints, e)
+			}
+		}
+	}
+	// Client.Allows trata a lista vazia como acesso a todas as rotas
+	if len(endpoints) == 0 {
+		return Client{}, ErrInsufficientScope
+	}
+
+	name := claims.ClientID
+	for _, alt := range []string{claims.AZP, claims.Subject} {
+		if name == "" {
+			name = alt
+		}
+	}
+	return Client{
+		Name:      name,
+		Tenant:    claims.Tenant,
+		Tier:      v.tier,
+		Endpoints: endpoints,
+		KeyID:     KeyID(claims.Issuer + " " + claims.Subject),
+		Method:    MethodJWT,
+	}, nil
+}
+
+// verify confere a assinatura e as claims registradas do token
+func (v *Verifier) verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	key, err := v.keys.lookup(ctx, header.Kid)
+	if err != nil {
+		return Claims{}, err
+	}
+	if key.alg != "" && key.alg != header.Alg {
+		return Claims{}, fmt.Errorf("%w: algorithm %s does not match the key", ErrInvalidToken, header.Alg)
+	}
+	if err := verifySignature(header.Alg, key.key, parts[0]+"."+parts[1], sig); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	now := v.now()
+	switch {
+	case claims.Issuer != v.issuer:
+		return Claims{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	case !slices.Contains(claims.Audience, v.audience):
+		return Claims{}, fmt.Errorf("%w: audience does not include %q", ErrInvalidToken, v.audience)
+	case claims.ExpiresAt == nil:
+		return Claims{}, fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	case now.After(unixTime(*claims.ExpiresAt).Add(v.leeway)):
+		return Claims{}, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	case claims.NotBefore != nil && now.Before(unixTime(*claims.NotBefore).Add(-v.leeway)):
+		return Claims{}, fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// verifySignature confere a assinatura RS256 (RSASSA-PKCS1-v1_5) ou ES256
+// (ECDSA P-256, r || s com 32 bytes cada) sobre "header.payload"
+// Outros algoritmos, incluindo "none", são recusados
+func verifySignature(alg string, key any, signed string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 requires an RSA key")
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) != nil {
+			return errors.New("bad signature")
+		}
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("ES256 requires an EC key")
+		}
+		if len(sig) != 64 {
+			return errors.New("bad signature")
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("bad signature")
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	return nil
+}
+
+// decodeSegment decodifica um segmento base64url do token em JSON
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// unixTime converte uma NumericDate (segundos desde a época, possivelmente fracionários)
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
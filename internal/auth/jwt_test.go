@@ -0,0 +1,337 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testIdP simula o provedor de identidade: gera as chaves, publica o JWKS e
+// assina os tokens
+type testIdP struct {
+	keys map[string]crypto.Signer // Chaves privadas pelo kid
+}
+
+// newTestIdP gera uma chave RSA ("rsa-1") e uma EC P-256 ("ec-1")
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testIdP{keys: map[string]crypto.Signer{"rsa-1": rsaKey, "ec-1": ecKey}}
+}
+
+// jwks retorna o documento JWKS com as chaves públicas
+func (p *testIdP) jwks() []byte {
+	enc := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	var keys []map[string]string
+	for kid, k := range p.keys {
+		switch pub := k.Public().(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+				"n": enc(pub.N.Bytes()), "e": enc(big.NewInt(int64(pub.E)).Bytes())})
+		case *ecdsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256",
+				"x": enc(pub.X.FillBytes(make([]byte, 32))), "y": enc(pub.Y.FillBytes(make([]byte, 32)))})
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"keys": keys})
+	return data
+}
+
+// sign emite um token assinado com a chave kid
+func (p *testIdP) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	alg := "RS256"
+	if _, ok := p.keys[kid].(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch k := p.keys[kid].(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// validClaims retorna claims aceitas pelo Verifier dos testes
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":       "https://idp.example.com",
+		"sub":       "svc-123",
+		"aud":       []string{"cep-weather", "other"},
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"client_id": "reports",
+		"scope":     "openid weather:read",
+		"tenant":    "acme-corp",
+	}
+}
+
+// newTestVerifier cria o Verifier com o JWKS servido por uma URL local
+func newTestVerifier(t *testing.T, idp *testIdP) *Verifier {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(idp.jwks())
+	}))
+	t.Cleanup(srv.Close)
+	scopes := map[string][]string{
+		"weather:read":  {"/weather", "/weather/{cep}"},
+		"weather:batch": {"/weather/batch"},
+	}
+	return NewVerifier(NewKeySet(srv.URL, time.Minute), "https://idp.example.com", "cep-weather", 30*time.Second, scopes, "premium")
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	idp := newTestIdP(t)
+	v := newTestVerifier(t, idp)
+	ctx := context.Background()
+
+	for _, kid := range []string{"rsa-1", "ec-1"} {
+		c, err := v.Verify(ctx, idp.sign(t, kid, validClaims()))
+		if err != nil {
+			t.Fatalf("%s: %v", kid, err)
+		}
+		if c.Name != "reports" || c.Tenant != "acme-corp" || c.Tier != "premium" || c.Method != MethodJWT || !c.Allows("/weather/{cep}") || c.Allows("/weather/batch") {
+			t.Fatalf("%s: unexpected client %+v", kid, c)
+		}
+	}
+
+	// Audiência em texto e nome do cliente a partir de sub
+	claims := validClaims()
+	claims["aud"] = "cep-weather"
+	delete(claims, "client_id")
+	if c, err := v.Verify(ctx, idp.sign(t, "ec-1", claims)); err != nil || c.Name != "svc-123" {
+		t.Fatalf("expected client named after sub, got %+v (%v)", c, err)
+	}
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	idp := newTestIdP(t)
+	v := newTestVerifier(t, idp)
+	ctx := context.Background()
+
+	with := func(key string, value any) string {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return idp.sign(t, "rsa-1", claims)
+	}
+	valid := idp.sign(t, "rsa-1", validClaims())
+	// Troca um caractere da assinatura por outro, sempre diferente do original
+	flip := "A"
+	if valid[len(valid)-60] == 'A' {
+		flip = "B"
+	}
+	other := newTestIdP(t).sign(t, "rsa-1", validClaims())
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa-1"}`))
+	payload, _ := json.Marshal(validClaims())
+
+	cases := map[string]string{
+		"malformed":           "not-a-jwt",
+		"wrong issuer":        with("iss", "https://evil.example.com"),
+		"wrong audience":      with("aud", "another-api"),
+		"expired":             with("exp", time.Now().Add(-time.Minute).Unix()),
+		"missing exp":         with("exp", nil),
+		"not valid yet":       with("nbf", time.Now().Add(time.Minute).Unix()),
+		"signed by other key": other,
+		"tampered signature":  valid[:len(valid)-60] + flip + valid[len(valid)-59:],
+		"tampered payload":    swapPayload(valid, with("sub", "admin")),
+		"alg none":            header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".",
+	}
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Verify(ctx, token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	// Dentro da tolerância de relógio, o token expirado ainda é aceito
+	if _, err := v.Verify(ctx, with("exp", time.Now().Add(-10*time.Second).Unix())); err != nil {
+		t.Fatalf("expected token within leeway to be accepted, got %v", err)
+	}
+	if _, err := v.Verify(ctx, with("scope", "openid profile")); !errors.Is(err, ErrInsufficientScope) {
+		t.Fatalf("expected ErrInsufficientScope, got %v", err)
+	}
+}
+
+// swapPayload troca o conteúdo do token pelo de outro, mantendo a assinatura
+func swapPayload(token, other string) string {
+	parts, otherParts := strings.Split(token, "."), strings.Split(other, ".")
+	return parts[0] + "." + otherParts[1] + "." + parts[2]
+}
+
+// TestKeySet_Rotation verifica que uma chave nova (kid desconhecido) é obtida
+// do provedor sem esperar o fim do cache, respeitando o intervalo mínimo
+func TestKeySet_Rotation(t *testing.T) {
+	idp := newTestIdP(t)
+	var fetches atomic.Int32
+	var doc atomic.Pointer[[]byte]
+	initial := idp.jwks()
+	doc.Store(&initial)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write(*doc.Load())
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	keys := NewKeySet(srv.URL, time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := keys.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	idp.keys["ec-2"] = rotated
+	updated := idp.jwks()
+	doc.Store(&updated)
+
+	// Logo após a carga, o kid desconhecido não provoca nova consulta
+	if _, err := keys.lookup(ctx, "ec-2"); !errors.Is(err, ErrInvalidToken) || fetches.Load() != 1 {
+		t.Fatalf("expected unknown kid without refetch, got %v after %d fetches", err, fetches.Load())
+	}
+	now = now.Add(minRefresh)
+	if _, err := keys.lookup(ctx, "ec-2"); err != nil || fetches.Load() != 2 {
+		t.Fatalf("expected rotated key after refetch, got %v after %d fetches", err, fetches.Load())
+	}
+
+	// Falhas na atualização mantêm as chaves atuais
+	broken := []byte("{")
+	doc.Store(&broken)
+	now = now.Add(time.Hour)
+	if _, err := keys.lookup(ctx, "ec-2"); err != nil {
+		t.Fatalf("expected cached keys to survive a failed refresh, got %v", err)
+	}
+}
+
+// TestKeySet_SlowRefresh verifica que uma atualização lenta do JWKS não
+// bloqueia os tokens com chaves em cache, é compartilhada pelos que dependem
+// dela e não é interrompida pelo cancelamento de quem a iniciou
+func TestKeySet_SlowRefresh(t *testing.T) {
+	idp := newTestIdP(t)
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		w.Write(idp.jwks())
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	var clock sync.Mutex
+	keys := NewKeySet(srv.URL, time.Hour)
+	keys.now = func() time.Time {
+		clock.Lock()
+		defer clock.Unlock()
+		return now
+	}
+	ctx := context.Background()
+	if err := keys.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	idp.keys["ec-2"] = rotated
+	clock.Lock()
+	now = now.Add(time.Hour)
+	clock.Unlock()
+
+	// O kid desconhecido inicia a atualização; quem a iniciou desiste dela
+	canceled, cancel := context.WithCancel(ctx)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := keys.lookup(canceled, "ec-2")
+		errs <- err
+	}()
+	for fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// Com a atualização em andamento, as chaves em cache continuam disponíveis
+	if _, err := keys.lookup(ctx, "rsa-1"); err != nil {
+		t.Fatalf("expected cached key during refresh, got %v", err)
+	}
+
+	// O kid desconhecido aguarda a mesma atualização, sem nova consulta
+	go func() {
+		_, err := keys.lookup(ctx, "ec-2")
+		errs <- err
+	}()
+	close(release)
+	if err := <-errs; err != nil || fetches.Load() != 2 {
+		t.Fatalf("expected rotated key from the shared refresh, got %v after %d fetches", err, fetches.Load())
+	}
+}
+
+func TestKeySet_File(t *testing.T) {
+	idp := newTestIdP(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, idp.jwks(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier(NewKeySet(path, time.Minute), "https://idp.example.com", "cep-weather", 0, map[string][]string{"weather:read": {"/weather"}}, "")
+	if _, err := v.Verify(context.Background(), idp.sign(t, "ec-1", validClaims())); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{
+		"not json":        "{",
+		"no keys":         `{"keys": []}`,
+		"small rsa key":   `{"keys": [{"kty": "RSA", "kid": "k", "n": "AQAB", "e": "AQAB"}]}`,
+		"point off curve": `{"keys": [{"kty": "EC", "kid": "k", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewKeySet(path, time.Minute).Load(context.Background()); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
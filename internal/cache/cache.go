@@ -0,0 +1,227 @@
+// Pacote cache fornece um cache em memória com expiração (TTL) e
+// deduplicação de carregamentos concorrentes
+//
+// É usado pelo Serviço B para evitar consultas repetidas às APIs externas
+// (ViaCEP e WeatherAPI): CEPs e cidades repetidos, seja em requisições
+// diferentes ou dentro de um mesmo lote, resultam em uma única chamada.
+//
+// Valores expirados são removidos periodicamente, mesmo que a chave não volte
+// a ser consultada, e o número de entradas pode ser limitado: ao atingir o
+// limite, a entrada usada há mais tempo é descartada (LRU).
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// entry armazena um valor e o instante em que ele expira
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // Zero significa que o valor nunca expira
+}
+
+// expired informa se o valor já expirou no instante informado
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// call representa um carregamento em andamento para uma chave
+// Chamadas concorrentes para a mesma chave aguardam o mesmo resultado
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Cache é um cache em memória seguro para uso concorrente
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int                 // 0 significa sem limite
+	entries    map[K]*list.Element // Elementos de order, com valor *entry[K, V]
+	order      *list.List          // Do usado mais recentemente ao usado há mais tempo
+	inflight   map[K]*call[V]
+	lastSweep  time.Time
+	now        func() time.Time // Relógio, substituível em testes
+}
+
+// sweepInterval é o intervalo mínimo entre as remoções de valores expirados
+const sweepInterval = time.Minute
+
+// New cria um cache cujos valores expiram após o ttl informado
+// Um ttl igual a 0 mantém os valores indefinidamente
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:      ttl,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+		inflight: make(map[K]*call[V]),
+		now:      time.Now,
+	}
+}
+
+// SetMaxEntries limita o número de valores armazenados
+// Ao atingir o limite, o valor usado há mais tempo é descartado; entradas
+// além do novo limite são removidas imediatamente. 0 remove o limite.
+func (c *Cache[K, V]) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = n
+	c.evict()
+}
+
+// Len retorna o número de valores armazenados, incluindo os expirados
+// ainda não removidos
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// SetTTL altera a validade dos valores armazenados a partir de agora
+// Valores já em cache mantêm a expiração calculada quando foram armazenados
+func (c *Cache[K, V]) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// Get retorna o valor armazenado para a chave, se existir e não tiver expirado
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(key)
+}
+
+// get deve ser chamado com c.mu travado
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if e.expired(c.now()) {
+		c.remove(el)
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Set armazena o valor para a chave
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value)
+}
+
+// set deve ser chamado com c.mu travado
+func (c *Cache[K, V]) set(key K, value V) {
+	now := c.now()
+	c.sweep(now)
+
+	e := &entry[K, V]{key: key, value: value}
+	if c.ttl > 0 {
+		e.expires = now.Add(c.ttl)
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	c.evict()
+}
+
+// remove descarta o elemento; deve ser chamado com c.mu travado
+func (c *Cache[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[K, V]).key)
+}
+
+// evict descarta os valores usados há mais tempo até respeitar o limite de
+// entradas; deve ser chamado com c.mu travado
+func (c *Cache[K, V]) evict() {
+	for c.maxEntries > 0 && len(c.entries) > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// sweep remove os valores expirados, evitando que chaves que não voltam a
+// ser consultadas ocupem memória indefinidamente; deve ser chamado com c.mu travado
+func (c *Cache[K, V]) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < sweepInterval {
+		return
+	}
+	c.lastSweep = now
+	for _, el := range c.entries {
+		if el.Value.(*entry[K, V]).expired(now) {
+			c.remove(el)
+		}
+	}
+}
+
+// GetOrLoad retorna o valor em cache ou o carrega usando a função load
+//
+// Se outra goroutine já estiver carregando a mesma chave, aguarda o resultado
+// dela em vez de repetir a chamada. Erros não são armazenados em cache.
+//
+// O carregamento recebe o contexto de quem o iniciou. Se esse contexto for
+// cancelado (ex: o cliente desconectou), os demais que aguardavam não herdam o
+// cancelamento: enquanto o próprio contexto estiver ativo, tentam novamente,
+// iniciando um novo carregamento. A espera também termina com o cancelamento
+// do próprio contexto.
+//
+// Retorna:
+//   - V: Valor em cache ou carregado
+//   - bool: true quando o valor veio do cache (sem chamar load)
+//   - error: Erro retornado por load ou pelo contexto
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, bool, error) {
+	for {
+		c.mu.Lock()
+		if v, ok := c.get(key); ok {
+			c.mu.Unlock()
+			return v, true, nil
+		}
+		if inflight, ok := c.inflight[key]; ok {
+			c.mu.Unlock()
+			select {
+			case <-inflight.done:
+			case <-ctx.Done():
+				var zero V
+				return zero, false, ctx.Err()
+			}
+			if canceled(inflight.err) && ctx.Err() == nil {
+				continue
+			}
+			return inflight.value, false, inflight.err
+		}
+		cl := &call[V]{done: make(chan struct{})}
+		c.inflight[key] = cl
+		c.mu.Unlock()
+
+		cl.value, cl.err = load(ctx)
+
+		c.mu.Lock()
+		if cl.err == nil {
+			c.set(key, cl.value)
+		}
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(cl.done)
+
+		return cl.value, false, cl.err
+	}
+}
+
+// canceled informa se o erro decorre do cancelamento ou do prazo de um contexto
+func canceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_Expiration(t *testing.T) {
+	now := time.Now()
+	c := New[string, int](time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected cached value 1, got %v/%v", v, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected value to expire")
+	}
+}
+
+func TestCache_NoTTL(t *testing.T) {
+	now := time.Now()
+	c := New[string, int](0)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(24 * 365 * time.Hour)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected value without TTL to never expire")
+	}
+}
+
+func TestCache_SetTTL(t *testing.T) {
+	now := time.Now()
+	c := New[string, int](time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.Set("old", 1)
+	c.SetTTL(time.Minute)
+	c.Set("new", 2)
+
+	// Apenas os valores armazenados após a alteração usam o novo ttl
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get("new"); ok {
+		t.Fatalf("expected value stored after SetTTL to expire")
+	}
+	if _, ok := c.Get("old"); !ok {
+		t.Fatalf("expected value stored before SetTTL to keep its expiration")
+	}
+}
+
+func TestCache_Sweep(t *testing.T) {
+	now := time.Now()
+	c := New[string, int](time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Valores expirados são removidos na próxima escrita, mesmo sem serem lidos
+	now = now.Add(2 * time.Minute)
+	c.Set("c", 3)
+	if n := c.Len(); n != 1 {
+		t.Fatalf("expected expired values to be swept, got %d entries", n)
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Fatalf("expected cached value 3, got %v/%v", v, ok)
+	}
+}
+
+func TestCache_MaxEntries(t *testing.T) {
+	c := New[string, int](0)
+	c.SetMaxEntries(2)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // "b" passa a ser o usado há mais tempo
+	c.Set("c", 3)
+
+	if n := c.Len(); n != 2 {
+		t.Fatalf("expected 2 entries, got %d", n)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected least recently used value to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Fatalf("expected %q to remain cached", key)
+		}
+	}
+
+	// Reduzir o limite descarta imediatamente as entradas excedentes
+	c.SetMaxEntries(1)
+	if _, ok := c.Get("a"); ok || c.Len() != 1 {
+		t.Fatalf("expected a single entry after lowering the limit, got %d", c.Len())
+	}
+}
+
+func TestCache_GetOrLoadDedupes(t *testing.T) {
+	c := New[string, int](time.Minute)
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _, err := c.GetOrLoad(context.Background(), "a", func(context.Context) (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("expected 42, got %v/%v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single load, got %d", calls.Load())
+	}
+	if _, cached, _ := c.GetOrLoad(context.Background(), "a", nil); !cached {
+		t.Fatalf("expected value to be served from cache")
+	}
+}
+
+func TestCache_GetOrLoadCanceledLoader(t *testing.T) {
+	c := New[string, int](time.Minute)
+	first, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	// O primeiro chamador inicia o carregamento e desiste antes do fim
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := c.GetOrLoad(first, "a", func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		firstErr <- err
+	}()
+	<-started
+
+	// O segundo aguarda o mesmo carregamento e não herda o cancelamento
+	secondDone := make(chan struct{})
+	var v int
+	var err error
+	go func() {
+		defer close(secondDone)
+		v, _, err = c.GetOrLoad(context.Background(), "a", func(context.Context) (int, error) {
+			return 42, nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the canceled caller to get context.Canceled, got %v", err)
+	}
+	<-secondDone
+	if err != nil || v != 42 {
+		t.Fatalf("expected the waiting caller to load 42, got %v/%v", v, err)
+	}
+}
+
+func TestCache_GetOrLoadWaiterCanceled(t *testing.T) {
+	c := New[string, int](time.Minute)
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go c.GetOrLoad(context.Background(), "a", func(context.Context) (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+	<-started
+
+	// Quem aguarda desiste com o próprio contexto, sem esperar o carregamento
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.GetOrLoad(ctx, "a", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	c := New[string, int](time.Minute)
+	boom := errors.New("boom")
+
+	if _, _, err := c.GetOrLoad(context.Background(), "a", func(context.Context) (int, error) { return 0, boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected error not to be cached")
+	}
+}
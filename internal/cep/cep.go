@@ -0,0 +1,64 @@
+// Pacote cep centraliza a interpretação e validação de CEPs brasileiros
+//
+// Aceita as formas que os usuários realmente digitam ("01310-100",
+// "01.310-100", " 01310100 ") e as normaliza para os 8 dígitos usados pelas
+// APIs externas. Além do formato, verifica se o CEP pertence a uma das faixas
+// atribuídas pelos Correios a alguma UF, usando uma tabela embarcada que também
+// permite inferir UF, região e capital sem consultar serviços externos.
+package cep
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Erros retornados por Parse
+// Ambos indicam um CEP inválido (422) e podem ser verificados com errors.Is
+var (
+	// ErrInvalidFormat indica que o texto não tem o formato de um CEP
+	ErrInvalidFormat = errors.New("cep must contain 8 digits, optionally formatted as 00000-000")
+	// ErrUnallocated indica que o CEP não pertence a nenhuma faixa atribuída a uma UF
+	ErrUnallocated = errors.New("cep is not within any range allocated to a Brazilian state")
+)
+
+// pattern reconhece um CEP com ou sem pontuação: 01310100, 01310-100 ou 01.310-100
+// Compilado uma única vez, evitando recompilar a expressão a cada requisição
+var pattern = regexp.MustCompile(`^(\d{2})\.?(\d{3})-?(\d{3})$`)
+
+// CEP representa um CEP já validado e normalizado (8 dígitos, sem pontuação)
+// Valores desse tipo devem ser obtidos via Parse
+type CEP string
+
+// Parse interpreta um CEP informado pelo usuário
+//
+// Espaços nas extremidades são removidos e a pontuação usual (ponto e hífen)
+// é aceita. O resultado é validado contra as faixas de CEP de cada UF.
+//
+// Retorna:
+//   - CEP: CEP normalizado com 8 dígitos
+//   - error: ErrInvalidFormat ou ErrUnallocated
+func Parse(s string) (CEP, error) {
+	m := pattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", ErrInvalidFormat
+	}
+	c := CEP(m[1] + m[2] + m[3])
+	if _, ok := lookup(c); !ok {
+		return "", ErrUnallocated
+	}
+	return c, nil
+}
+
+// String retorna o CEP com 8 dígitos, sem pontuação (ex: "01310100")
+func (c CEP) String() string {
+	return string(c)
+}
+
+// Formatted retorna o CEP no formato 00000-000 (ex: "01310-100")
+func (c CEP) Formatted() string {
+	if len(c) != 8 {
+		return string(c)
+	}
+	return string(c[:5]) + "-" + string(c[5:])
+}
@@ -0,0 +1,130 @@
+package cep
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"01310100":      "01310100",
+		"01310-100":     "01310100",
+		"01.310-100":    "01310100",
+		" 01310100 ":    "01310100",
+		"\t29902-555\n": "29902555",
+		"99999999":      "99999999",
+	}
+	for in, want := range cases {
+		got, err := Parse(in)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", in, err)
+			continue
+		}
+		if got.String() != want {
+			t.Errorf("Parse(%q): expected %s, got %s", in, want, got)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]error{
+		"":          ErrInvalidFormat,
+		"1234567":   ErrInvalidFormat,
+		"123456789": ErrInvalidFormat,
+		"0131O100":  ErrInvalidFormat,
+		"013-10100": ErrInvalidFormat,
+		"01310 100": ErrInvalidFormat,
+		"00000000":  ErrUnallocated,
+		"00999-999": ErrUnallocated,
+	}
+	for in, want := range cases {
+		if _, err := Parse(in); !errors.Is(err, want) {
+			t.Errorf("Parse(%q): expected %v, got %v", in, want, err)
+		}
+	}
+}
+
+func TestFormatted(t *testing.T) {
+	c, err := Parse("01310100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Formatted() != "01310-100" {
+		t.Fatalf("expected 01310-100, got %s", c.Formatted())
+	}
+}
+
+func TestStateRegionCapital(t *testing.T) {
+	cases := []struct {
+		in      string
+		state   string
+		region  Region
+		capital bool
+	}{
+		{"01310-100", "SP", RegionSudeste, true},
+		{"13015-000", "SP", RegionSudeste, false},
+		{"29902-555", "ES", RegionSudeste, false},
+		{"40010-000", "BA", RegionNordeste, true},
+		{"69301-000", "RR", RegionNorte, true},
+		{"69400-000", "AM", RegionNorte, false},
+		{"70040-010", "DF", RegionCentroOeste, true},
+		{"72800-000", "GO", RegionCentroOeste, false},
+		{"90010-000", "RS", RegionSul, true},
+	}
+	for _, c := range cases {
+		got, err := Parse(c.in)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", c.in, err)
+			continue
+		}
+		if got.State() != c.state || got.Region() != c.region || got.IsCapital() != c.capital {
+			t.Errorf("%s: expected %s/%s/%v, got %s/%s/%v", c.in, c.state, c.region, c.capital,
+				got.State(), got.Region(), got.IsCapital())
+		}
+	}
+}
+
+func TestRangesCoverEveryState(t *testing.T) {
+	states := map[string]bool{}
+	for _, f := range faixasUF {
+		states[f.uf] = true
+	}
+	if len(states) != 27 {
+		t.Fatalf("expected 27 UFs in the range table, got %d", len(states))
+	}
+	for _, c := range faixasCapital {
+		if !states[c.uf] {
+			t.Errorf("capital range for unknown UF %s", c.uf)
+		}
+	}
+}
+
+func TestTimeZone(t *testing.T) {
+	cases := map[string]string{
+		"01310-100": "America/Sao_Paulo",
+		"40010-000": "America/Bahia",
+		"69400-000": "America/Manaus",
+		"69900-000": "America/Rio_Branco",
+	}
+	for in, want := range cases {
+		c, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error %v", in, err)
+		}
+		if got := c.TimeZone(); got != want {
+			t.Errorf("%s: expected %s, got %s", in, want, got)
+		}
+	}
+
+	// Todas as UFs da tabela de faixas possuem um fuso válido
+	for _, f := range faixasUF {
+		name, ok := fusos[f.uf]
+		if !ok {
+			t.Fatalf("missing time zone for %s", f.uf)
+		}
+		if _, err := time.LoadLocation(name); err != nil {
+			t.Errorf("%s: invalid time zone %q: %v", f.uf, name, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package cep
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Region representa uma das cinco grandes regiões do Brasil
+type Region string
+
+// Regiões brasileiras, conforme a divisão do IBGE
+const (
+	RegionNorte       Region = "Norte"
+	RegionNordeste    Region = "Nordeste"
+	RegionCentroOeste Region = "Centro-Oeste"
+	RegionSudeste     Region = "Sudeste"
+	RegionSul         Region = "Sul"
+)
+
+// faixasCSV contém a tabela oficial de faixas de CEP por UF e por capital
+// O arquivo é embarcado no binário, permitindo a consulta sem acesso à rede
+//
+//go:embed faixas.csv
+var faixasCSV string
+
+// faixa representa um intervalo contínuo de CEPs (limites inclusivos)
+type faixa struct {
+	uf         string
+	region     Region
+	start, end int
+}
+
+// contains verifica se o CEP (convertido para inteiro) pertence à faixa
+func (f faixa) contains(n int) bool {
+	return n >= f.start && n <= f.end
+}
+
+// Tabelas carregadas a partir de faixasCSV na inicialização do pacote
+// Algumas UFs possuem mais de um intervalo (ex: AM, DF, GO)
+var faixasUF, faixasCapital = mustLoad(faixasCSV)
+
+// mustLoad interpreta a tabela embarcada de faixas
+// Como o arquivo faz parte do binário, qualquer erro é de programação e
+// interrompe a inicialização
+func mustLoad(data string) (ufs, capitais []faixa) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = 5
+
+	records, err := r.ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("cep: tabela de faixas inválida: %v", err))
+	}
+	for _, rec := range records {
+		start, err1 := toInt(rec[2])
+		end, err2 := toInt(rec[3])
+		if err1 != nil || err2 != nil || start > end {
+			panic(fmt.Sprintf("cep: faixa inválida na tabela: %v", rec))
+		}
+		f := faixa{uf: rec[0], region: Region(rec[1]), start: start, end: end}
+		switch rec[4] {
+		case "uf":
+			ufs = append(ufs, f)
+		case "capital":
+			capitais = append(capitais, f)
+		default:
+			panic(fmt.Sprintf("cep: tipo de faixa desconhecido: %q", rec[4]))
+		}
+	}
+	return ufs, capitais
+}
+
+// toInt converte um CEP ("01000-000" ou "01000000") para inteiro
+func toInt(s string) (int, error) {
+	return strconv.Atoi(strings.ReplaceAll(s, "-", ""))
+}
+
+// lookup encontra a faixa de UF à qual o CEP pertence
+func lookup(c CEP) (faixa, bool) {
+	n, err := toInt(string(c))
+	if err != nil || len(c) != 8 {
+		return faixa{}, false
+	}
+	for _, f := range faixasUF {
+		if f.contains(n) {
+			return f, true
+		}
+	}
+	return faixa{}, false
+}
+
+// State retorna a sigla da UF do CEP (ex: "SP")
+// Retorna "" para valores que não foram obtidos via Parse
+func (c CEP) State() string {
+	f, _ := lookup(c)
+	return f.uf
+}
+
+// Region retorna a região do Brasil à qual o CEP pertence
+// Retorna "" para valores que não foram obtidos via Parse
+func (c CEP) Region() Region {
+	f, _ := lookup(c)
+	return f.region
+}
+
+// IsCapital verifica se o CEP pertence à faixa da capital da sua UF
+func (c CEP) IsCapital() bool {
+	n, err := toInt(string(c))
+	if err != nil || len(c) != 8 {
+		return false
+	}
+	for _, f := range faixasCapital {
+		if f.contains(n) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package cep
+
+// fusos mapeia cada UF para o fuso horário IANA da sua capital
+// Alguns municípios do oeste do Amazonas seguem o horário do Acre, mas o
+// fuso da capital representa a grande maioria dos CEPs de cada UF
+var fusos = map[string]string{
+	"AC": "America/Rio_Branco",
+	"AL": "America/Maceio",
+	"AM": "America/Manaus",
+	"AP": "America/Belem",
+	"BA": "America/Bahia",
+	"CE": "America/Fortaleza",
+	"DF": "America/Sao_Paulo",
+	"ES": "America/Sao_Paulo",
+	"GO": "America/Sao_Paulo",
+	"MA": "America/Fortaleza",
+	"MG": "America/Sao_Paulo",
+	"MS": "America/Campo_Grande",
+	"MT": "America/Cuiaba",
+	"PA": "America/Belem",
+	"PB": "America/Fortaleza",
+	"PE": "America/Recife",
+	"PI": "America/Fortaleza",
+	"PR": "America/Sao_Paulo",
+	"RJ": "America/Sao_Paulo",
+	"RN": "America/Fortaleza",
+	"RO": "America/Porto_Velho",
+	"RR": "America/Boa_Vista",
+	"RS": "America/Sao_Paulo",
+	"SC": "America/Sao_Paulo",
+	"SE": "America/Maceio",
+	"SP": "America/Sao_Paulo",
+	"TO": "America/Araguaina",
+}
+
+// TimeZone retorna o nome IANA do fuso horário da UF do CEP (ex: "America/Sao_Paulo")
+// Retorna "" para valores que não foram obtidos via Parse
+func (c CEP) TimeZone() string {
+	return fusos[c.State()]
+}
@@ -0,0 +1,180 @@
+// Pacote certs fornece a configuração TLS da comunicação entre os serviços
+//
+// O Reloader carrega o certificado do serviço, a sua chave privada e a CA
+// (autoridade certificadora) usada para verificar o outro lado, e os recarrega
+// quando os arquivos são alterados (ver Reloader.Watch). Assim, certificados
+// renovados (ex: pelo cert-manager) passam a valer nas novas conexões sem
+// reiniciar os serviços.
+//
+// No servidor, a CA ativa o TLS mútuo (mTLS): apenas clientes com certificado
+// emitido por ela são aceitos. No cliente, a CA substitui as raízes do sistema
+// na verificação do servidor e o certificado, quando informado, é apresentado
+// ao servidor.
+package certs
+
+import (
+	"cep-weather/internal/config"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// material é o conjunto de certificados carregado dos arquivos
+type material struct {
+	cert *tls.Certificate // Certificado do serviço (nil quando não configurado)
+	pool *x509.CertPool   // CA usada na verificação do outro lado (nil usa as raízes do sistema)
+}
+
+// Reloader mantém os certificados atualizados conforme os arquivos
+// A troca é atômica e vale para as conexões seguintes; o Reloader é seguro
+// para uso concorrente
+type Reloader struct {
+	certFile, keyFile, caFile string
+	current                   atomic.Pointer[material]
+}
+
+// NewReloader carrega os certificados
+//
+// Parâmetros:
+//   - certFile, keyFile: Certificado do serviço e a sua chave privada, em PEM (ambos ou nenhum)
+//   - caFile: CA que emitiu os certificados do outro lado, em PEM ("" usa as raízes do sistema)
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("certificate and key files must be set together")
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload lê novamente os arquivos
+// Arquivos inválidos (ex: certificado renovado sem a chave correspondente)
+// são rejeitados, mantendo os certificados atuais
+func (r *Reloader) Reload() error {
+	var m material
+	if r.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("certificate %s: %w", r.certFile, err)
+		}
+		m.cert = &cert
+	}
+	if r.caFile != "" {
+		data, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("ca %s: %w", r.caFile, err)
+		}
+		m.pool = x509.NewCertPool()
+		if !m.pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("ca %s: no PEM certificates found", r.caFile)
+		}
+	}
+	r.current.Store(&m)
+	return nil
+}
+
+// Watch recarrega os certificados sempre que um dos arquivos for alterado,
+// até que ctx seja cancelado (ver config.Watch)
+// Falhas na recarga são registradas no log e mantêm os certificados atuais
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	for _, path := range []string{r.certFile, r.keyFile, r.caFile} {
+		if path == "" {
+			continue
+		}
+		go config.Watch(ctx, path, interval, func() {
+			if err := r.Reload(); err != nil {
+				log.Printf("Certificados inválidos; mantendo os atuais: %v", err)
+				return
+			}
+			log.Printf("Certificados recarregados (%s alterado)", path)
+		})
+	}
+}
+
+// ServerConfig retorna a configuração TLS do servidor
+// Com a CA configurada, exige o certificado do cliente (mTLS)
+func (r *Reloader) ServerConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.current.Load().cert, nil
+		},
+	}
+	if r.caFile != "" {
+		// A verificação é feita em VerifyConnection, com a CA atual; ClientCAs
+		// fixaria a CA carregada na criação da configuração
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			return r.verify(cs, "", x509.ExtKeyUsageClientAuth)
+		}
+	}
+	return cfg
+}
+
+// ClientConfig retorna a configuração TLS do cliente
+//
+// Parâmetros:
+//   - serverName: Nome ou IP esperado no certificado do servidor (ex: "service-b"),
+//     obrigatório com a CA; também é enviado no SNI
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.current.Load().cert; cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+	if r.caFile != "" {
+		// A verificação padrão usaria RootCAs, fixada na criação da
+		// configuração; VerifyConnection verifica com a CA atual, inclusive o
+		// nome do servidor. O nome vem de serverName, e não da conexão: para
+		// endereços IP, cs.ServerName fica vazio (o IP não vai no SNI) e a
+		// verificação aceitaria qualquer certificado da CA
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if serverName == "" {
+				return errors.New("tls: server name is required to verify the server certificate")
+			}
+			return r.verify(cs, serverName, x509.ExtKeyUsageServerAuth)
+		}
+	}
+	return cfg
+}
+
+// verify confere a cadeia do certificado do outro lado com a CA atual
+func (r *Reloader) verify(cs tls.ConnectionState, dnsName string, usage x509.ExtKeyUsage) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: peer did not present a certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         r.current.Load().pool,
+		Intermediates: intermediates,
+		DNSName:       dnsName,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	return err
+}
+
+// PeerSubject retorna o subject do certificado apresentado pelo outro lado da
+// conexão (ex: "CN=service-a,O=cep-weather"), ou "" quando não há certificado
+func PeerSubject(cs *tls.ConnectionState) string {
+	if cs == nil || len(cs.PeerCertificates) == 0 {
+		return ""
+	}
+	return cs.PeerCertificates[0].Subject.String()
+}
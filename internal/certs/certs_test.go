@@ -0,0 +1,208 @@
+package certs
+
+import (
+	"cep-weather/internal/certs/certstest"
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// serveTLS inicia um servidor HTTPS local que responde com o subject do
+// certificado do cliente
+func serveTLS(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, PeerSubject(r.TLS))
+		}),
+		ErrorLog: discardLog(),
+	}
+	go srv.Serve(tls.NewListener(lis, cfg))
+	t.Cleanup(func() { srv.Close() })
+	return "https://" + lis.Addr().String()
+}
+
+// discardLog descarta os erros de handshake esperados nos testes
+func discardLog() *log.Logger {
+	return log.New(io.Discard, "", 0)
+}
+
+// get faz uma requisição com a configuração TLS do cliente e retorna o corpo
+func get(url string, cfg *tls.Config) (string, error) {
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}, Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := certstest.NewCA(t, "test-ca")
+	caFile := ca.WriteCA(t, dir)
+	serverCert, serverKey := ca.Issue(t, "service-b").Write(t, dir, "server")
+	clientCert, clientKey := ca.Issue(t, "service-a").Write(t, dir, "client")
+
+	server, err := NewReloader(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serveTLS(t, server.ServerConfig())
+
+	client, err := NewReloader(clientCert, clientKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, err := get(url, client.ClientConfig("127.0.0.1")); err != nil || subject != "CN=service-a,O=cep-weather" {
+		t.Fatalf("expected mTLS with client subject, got %q (%v)", subject, err)
+	}
+
+	// Sem certificado, com certificado de outra CA ou com outro nome de servidor, a conexão é recusada
+	other := certstest.NewCA(t, "other-ca")
+	otherDir := t.TempDir()
+	otherCert, otherKey := other.Issue(t, "intruder").Write(t, otherDir, "client")
+	noCert, _ := NewReloader("", "", caFile)
+	foreign, _ := NewReloader(otherCert, otherKey, caFile)
+	cases := map[string]*tls.Config{
+		"no client certificate":  noCert.ClientConfig("127.0.0.1"),
+		"foreign client":         foreign.ClientConfig("127.0.0.1"),
+		"server name mismatch":   client.ClientConfig("service-c"),
+		"system roots on client": {},
+	}
+	for name, cfg := range cases {
+		if _, err := get(url, cfg); err == nil {
+			t.Errorf("%s: expected handshake failure", name)
+		}
+	}
+}
+
+// TestReloader_ServerName verifica que o nome esperado é conferido mesmo
+// quando o endereço é um IP, que não vai no SNI
+func TestReloader_ServerName(t *testing.T) {
+	dir := t.TempDir()
+	ca := certstest.NewCA(t, "test-ca")
+	caFile := ca.WriteCA(t, dir)
+	serviceB, serviceBKey := ca.IssueOnly(t, "service-b", "service-b").Write(t, dir, "service-b")
+	serviceA, serviceAKey := ca.IssueOnly(t, "service-a", "service-a").Write(t, dir, "service-a")
+
+	client, err := NewReloader("", "", caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewReloader(serviceB, serviceBKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serveTLS(t, server.ServerConfig())
+	if _, err := get(url, client.ClientConfig("service-b")); err != nil {
+		t.Fatalf("expected connection with the expected name, got %v", err)
+	}
+	for _, name := range []string{"127.0.0.1", ""} {
+		if _, err := get(url, client.ClientConfig(name)); err == nil {
+			t.Errorf("%q: expected certificate without matching SAN to be rejected", name)
+		}
+	}
+
+	// Outro certificado da mesma CA (ex: o do Serviço A) não se passa pelo Serviço B
+	impostor, err := NewReloader(serviceA, serviceAKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(serveTLS(t, impostor.ServerConfig()), client.ClientConfig("service-b")); err == nil {
+		t.Fatalf("expected certificate issued to another service to be rejected")
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	ca := certstest.NewCA(t, "test-ca")
+	caFile := ca.WriteCA(t, dir)
+	serverCert, serverKey := ca.Issue(t, "service-b").Write(t, dir, "server")
+
+	server, err := NewReloader(serverCert, serverKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // Watch registra a versão inicial dos arquivos
+	url := serveTLS(t, server.ServerConfig())
+
+	client, err := NewReloader("", "", caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(url, client.ClientConfig("127.0.0.1")); err != nil {
+		t.Fatalf("expected TLS connection, got %v", err)
+	}
+
+	// Rotação da CA: o servidor passa a usar um certificado da nova CA, que o
+	// cliente só aceita depois de recarregar o arquivo da CA
+	rotated := certstest.NewCA(t, "rotated-ca")
+	rotated.Issue(t, "service-b").Write(t, dir, "server")
+	waitFor(t, func() bool {
+		_, err := get(url, client.ClientConfig("127.0.0.1"))
+		return err != nil
+	})
+	rotated.WriteCA(t, dir)
+	if err := client.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(url, client.ClientConfig("127.0.0.1")); err != nil {
+		t.Fatalf("expected connection after reloading the CA, got %v", err)
+	}
+
+	// Um certificado sem a chave correspondente é rejeitado, mantendo o atual
+	if err := os.WriteFile(serverCert, ca.Issue(t, "service-b").CertPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Reload(); err == nil {
+		t.Fatalf("expected mismatched key pair to be rejected")
+	}
+	if _, err := get(url, client.ClientConfig("127.0.0.1")); err != nil {
+		t.Fatalf("expected current certificate to be kept, got %v", err)
+	}
+}
+
+func TestNewReloader_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	cert, _ := certstest.NewCA(t, "ca").Issue(t, "svc").Write(t, dir, "svc")
+	notPEM := dir + "/not-pem.txt"
+	os.WriteFile(notPEM, []byte("not a certificate"), 0o600)
+
+	cases := map[string][3]string{
+		"cert without key": {cert, "", ""},
+		"missing files":    {dir + "/missing.pem", dir + "/missing-key.pem", ""},
+		"ca without pem":   {"", "", notPEM},
+	}
+	for name, files := range cases {
+		if _, err := NewReloader(files[0], files[1], files[2]); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// waitFor aguarda a condição por até 2s
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatalf("condition not met in time")
+}
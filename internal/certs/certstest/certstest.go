@@ -0,0 +1,145 @@
+// Pacote certstest gera CAs e certificados efêmeros para os testes com TLS
+//
+// As chaves são ECDSA P-256 e os certificados valem por uma hora, para
+// localhost e 127.0.0.1, além dos nomes informados (ver CA.Issue). Nada é gravado fora dos
+// diretórios temporários dos testes.
+package certstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// CA é uma autoridade certificadora efêmera
+type CA struct {
+	Cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	PEM  []byte // Certificado da CA em PEM
+}
+
+// Pair é um certificado emitido pela CA e a sua chave privada, em PEM
+type Pair struct {
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// NewCA gera uma CA autoassinada com o nome informado
+func NewCA(t testing.TB, name string) *CA {
+	t.Helper()
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial(t),
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"cep-weather"}},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &CA{Cert: cert, key: key, PEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// Issue emite um certificado para uso como servidor e como cliente, válido
+// para localhost, 127.0.0.1 e ::1
+//
+// Parâmetros:
+//   - commonName: Nome do titular (ex: "service-a"), registrado no subject
+//   - dnsNames: Nomes adicionais aceitos na verificação do servidor (ex: "service-b")
+func (ca *CA) Issue(t testing.TB, commonName string, dnsNames ...string) Pair {
+	t.Helper()
+	return ca.issue(t, commonName, append([]string{"localhost"}, dnsNames...), []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback})
+}
+
+// IssueOnly emite um certificado válido apenas para os nomes informados,
+// sem os endereços locais (ex: para testar a recusa de um nome diferente)
+func (ca *CA) IssueOnly(t testing.TB, commonName string, dnsNames ...string) Pair {
+	t.Helper()
+	return ca.issue(t, commonName, dnsNames, nil)
+}
+
+// issue emite o certificado com os nomes e IPs informados
+func (ca *CA) issue(t testing.TB, commonName string, dnsNames []string, ips []net.IP) Pair {
+	t.Helper()
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(t),
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"cep-weather"}},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.Cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Pair{
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// WriteCA grava o certificado da CA em dir e retorna o caminho do arquivo
+func (ca *CA) WriteCA(t testing.TB, dir string) string {
+	t.Helper()
+	return write(t, filepath.Join(dir, "ca.pem"), ca.PEM)
+}
+
+// Write grava o certificado e a chave em dir, como <name>.pem e <name>-key.pem
+func (p Pair) Write(t testing.TB, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	return write(t, filepath.Join(dir, name+".pem"), p.CertPEM), write(t, filepath.Join(dir, name+"-key.pem"), p.KeyPEM)
+}
+
+// write grava o arquivo com permissão restrita
+func write(t testing.TB, path string, data []byte) string {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newKey gera uma chave ECDSA P-256
+func newKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// serial gera um número de série aleatório de 128 bits
+func serial(t testing.TB) *big.Int {
+	t.Helper()
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
@@ -0,0 +1,259 @@
+// Pacote config centraliza a configuração dos serviços em structs tipadas.
+//
+// Os valores são carregados em três camadas, cada uma sobrescrevendo a anterior:
+//  1. Valores padrão (DefaultServiceA, DefaultServiceB)
+//  2. Arquivo opcional em YAML (.yaml/.yml) ou JSON (.json), indicado por CONFIG_FILE
+//  3. Variáveis de ambiente (tag "env" de cada campo)
+//
+// A configuração é validada na inicialização: valores inválidos interrompem o
+// serviço com a lista completa de problemas, em vez de falhar na primeira
+// requisição. Segredos (tipo Secret) nunca são exibidos por Format.
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv é a variável de ambiente com o caminho do arquivo de configuração
+const FileEnv = "CONFIG_FILE"
+
+// Duration é uma duração configurável como texto ("10s", "5m", "24h")
+// tanto no arquivo (YAML ou JSON) quanto nas variáveis de ambiente
+type Duration time.Duration
+
+// MarshalText formata a duração no mesmo formato aceito na leitura
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText interpreta a duração no formato de time.ParseDuration
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Secret é um valor sensível (ex: chave de API)
+// É lido normalmente, mas exibido apenas como "[REDACTED]"
+type Secret string
+
+// redacted substitui o valor dos segredos na exibição da configuração
+const redacted = "[REDACTED]"
+
+// String oculta o valor do segredo, evitando vazamentos em logs
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString oculta o valor do segredo também na formatação %#v
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+// MarshalText oculta o valor do segredo na serialização (ver Format)
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText lê o valor do segredo sem alterações
+func (s *Secret) UnmarshalText(text []byte) error {
+	*s = Secret(text)
+	return nil
+}
+
+// Value retorna o valor real do segredo
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// Validator é implementado pelas configurações dos serviços
+type Validator interface {
+	Validate() error
+}
+
+// Load preenche cfg a partir do arquivo de configuração (quando informado) e
+// das variáveis de ambiente, e valida o resultado
+//
+// cfg deve ser um ponteiro para a configuração já preenchida com os valores
+// padrão. Campos desconhecidos no arquivo são rejeitados, evitando que erros
+// de digitação passem despercebidos.
+//
+// Parâmetros:
+//   - cfg: Ponteiro para a configuração a preencher, com os valores padrão (ver DefaultServiceB)
+//   - path: Caminho do arquivo YAML ou JSON ("" para usar apenas o ambiente)
+//
+// Retorna erro descrevendo todos os valores inválidos encontrados
+func Load(cfg Validator, path string) error {
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+	if err := loadEnv(reflect.ValueOf(cfg).Elem(), ""); err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// loadFile decodifica o arquivo de configuração conforme a extensão
+func loadFile(cfg any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(cfg)
+	default:
+		return fmt.Errorf("unsupported extension %q (use .yaml, .yml or .json)", ext)
+	}
+}
+
+// loadEnv percorre os campos da struct e aplica as variáveis de ambiente
+// indicadas pela tag "env"; structs aninhadas são percorridas recursivamente
+//
+// A tag "envprefix" de uma struct aninhada é acrescentada ao nome das
+// variáveis dos seus campos, permitindo reutilizar o mesmo tipo em mais de
+// um campo (ex: VIACEP_RATE_LIMIT e WEATHERAPI_RATE_LIMIT)
+func loadEnv(v reflect.Value, prefix string) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		name := field.Tag.Get("env")
+		if name == "" {
+			if value.Kind() == reflect.Struct {
+				errs = append(errs, loadEnv(value, prefix+field.Tag.Get("envprefix")))
+			}
+			continue
+		}
+		name = prefix + name
+		raw, ok := os.LookupEnv(name)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := setValue(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EnvOverrides lista as opções de cfg definidas por variáveis de ambiente,
+// que têm precedência sobre o arquivo de configuração
+//
+// Retorna um mapa do nome da opção no arquivo (ex: "cache.weather_ttl") para
+// o nome da variável (ex: "WEATHER_CACHE_TTL"); variáveis vazias são ignoradas,
+// assim como em Load.
+func EnvOverrides(cfg any) map[string]string {
+	overrides := make(map[string]string)
+	envOverrides(reflect.Indirect(reflect.ValueOf(cfg)).Type(), "", "", overrides)
+	return overrides
+}
+
+// envOverrides percorre os campos da struct como loadEnv, acumulando em
+// overrides as variáveis de ambiente definidas
+func envOverrides(t reflect.Type, path, prefix string, overrides map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		option, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if path != "" {
+			option = path + "." + option
+		}
+		name := field.Tag.Get("env")
+		if name == "" {
+			if field.Type.Kind() == reflect.Struct {
+				envOverrides(field.Type, option, prefix+field.Tag.Get("envprefix"), overrides)
+			}
+			continue
+		}
+		if os.Getenv(prefix+name) != "" {
+			overrides[option] = prefix + name
+		}
+	}
+}
+
+// setValue converte o texto da variável de ambiente para o tipo do campo
+func setValue(v reflect.Value, raw string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		// Listas são informadas separadas por vírgula: "a,b,c"
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		list := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(list.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(list)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// Format devolve a configuração efetiva em YAML, com os segredos ocultos
+// Usado para registrar no log a configuração aplicada na inicialização
+func Format(cfg any) string {
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Sprintf("<erro ao formatar a configuração: %v>", err)
+	}
+	return string(out)
+}
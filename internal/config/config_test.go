@@ -0,0 +1,275 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile cria um arquivo de configuração temporário
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg := DefaultServiceA()
+	if err := Load(&cfg, ""); err != nil {
+		t.Fatalf("expected defaults to be valid, got %v", err)
+	}
+	if cfg.Port != "8080" || time.Duration(cfg.ServiceB.Timeout) != 10*time.Second {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestLoad_FileAndEnv(t *testing.T) {
+	yamlFile := writeFile(t, "service-b.yaml", `
+port: "9090"
+weather_api_key: from-file
+batch_concurrency: 4
+cache:
+  weather_ttl: 1m
+units:
+  legacy_kelvin: true
+`)
+	jsonFile := writeFile(t, "service-b.json", `{"port": "9090", "weather_api_key": "from-file", "batch_concurrency": 4,
+		"cache": {"weather_ttl": "1m"}, "units": {"legacy_kelvin": true}}`)
+
+	for _, path := range []string{yamlFile, jsonFile} {
+		// O ambiente tem precedência sobre o arquivo
+		t.Setenv("BATCH_CONCURRENCY", "16")
+		t.Setenv("LOCATION_CACHE_TTL", "2h")
+
+		cfg := DefaultServiceB()
+		if err := Load(&cfg, path); err != nil {
+			t.Fatalf("%s: unexpected error %v", path, err)
+		}
+		if cfg.Port != "9090" || cfg.WeatherAPIKey.Value() != "from-file" || !cfg.Units.LegacyKelvin {
+			t.Errorf("%s: file values not applied: %+v", path, cfg)
+		}
+		if cfg.BatchConcurrency != 16 || time.Duration(cfg.Cache.LocationTTL) != 2*time.Hour {
+			t.Errorf("%s: env values not applied: %+v", path, cfg)
+		}
+		if time.Duration(cfg.Cache.WeatherTTL) != time.Minute || cfg.GRPCPort != "50051" || cfg.Units.Precision != 2 {
+			t.Errorf("%s: expected untouched fields to keep defaults: %+v", path, cfg)
+		}
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("PORT", "99999")
+	t.Setenv("SERVICE_B_PROTOCOL", "ftp")
+	t.Setenv("SERVICE_B_TIMEOUT", "-1s")
+	t.Setenv("OTEL_PROPAGATORS", "tracecontext,jaeger")
+	t.Setenv("TRACE_SAMPLER_RATIO", "1.5")
+	t.Setenv("RATE_LIMIT_KEY_TIER", "gold")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,proxy.local")
+	t.Setenv("AUTH_ENABLED", "true")
+
+	cfg := DefaultServiceA()
+	err := Load(&cfg, "")
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	// Todos os problemas são informados de uma vez
+	for _, want := range []string{"port:", "service_b.protocol:", "service_b.timeout:", "sampler_ratio:", "rate_limit.key_tier:", "rate_limit.trusted_proxies:", "auth.keys_file:", "propagators:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	cases := map[string]func(t *testing.T) error{
+		"unknown field": func(t *testing.T) error {
+			cfg := DefaultServiceA()
+			return Load(&cfg, writeFile(t, "a.yaml", "prot: 8080\n"))
+		},
+		"bad duration": func(t *testing.T) error {
+			t.Setenv("SERVICE_B_TIMEOUT", "ten seconds")
+			cfg := DefaultServiceA()
+			return Load(&cfg, "")
+		},
+		"bad extension": func(t *testing.T) error {
+			cfg := DefaultServiceA()
+			return Load(&cfg, writeFile(t, "a.toml", ""))
+		},
+		"missing file": func(t *testing.T) error {
+			cfg := DefaultServiceA()
+			return Load(&cfg, filepath.Join(t.TempDir(), "missing.yaml"))
+		},
+		"missing api key": func(t *testing.T) error {
+			cfg := DefaultServiceB()
+			return Load(&cfg, "")
+		},
+		"unknown air quality provider": func(t *testing.T) error {
+			t.Setenv("WEATHER_API_KEY", "abc")
+			t.Setenv("AIR_QUALITY_PROVIDERS", "weatherapi,airnow")
+			cfg := DefaultServiceB()
+			return Load(&cfg, "")
+		},
+		"duplicate air quality provider": func(t *testing.T) error {
+			t.Setenv("WEATHER_API_KEY", "abc")
+			t.Setenv("AIR_QUALITY_PROVIDERS", "open-meteo,open-meteo")
+			cfg := DefaultServiceB()
+			return Load(&cfg, "")
+		},
+	}
+	for name, load := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := load(t); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestServiceB_APIKeys(t *testing.T) {
+	t.Setenv("WEATHER_API_KEYS", "key-b, key-c")
+	cfg := DefaultServiceB()
+	if err := Load(&cfg, writeFile(t, "service-b.yaml", "weather_api_key: key-a\nkey_strategy: least-used\n")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := strings.Join(cfg.APIKeys(), ","); got != "key-a,key-b,key-c" {
+		t.Fatalf("unexpected keys %s", got)
+	}
+
+	// weather_api_keys dispensa weather_api_key; a estratégia é validada
+	t.Setenv("WEATHER_API_KEY_STRATEGY", "random")
+	cfg = DefaultServiceB()
+	err := Load(&cfg, "")
+	if err == nil || strings.Contains(err.Error(), "weather_api_key:") || !strings.Contains(err.Error(), "key_strategy:") {
+		t.Fatalf("expected only key_strategy error, got %v", err)
+	}
+}
+
+func TestLoad_EnvPrefix(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "key")
+	t.Setenv("VIACEP_RATE_LIMIT", "2.5")
+	t.Setenv("WEATHERAPI_RATE_MODE", "fail-fast")
+
+	cfg := DefaultServiceB()
+	if err := Load(&cfg, ""); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// Cada variável se aplica apenas à API do seu prefixo
+	viacep, weatherapi := cfg.RateLimits.ViaCEP, cfg.RateLimits.WeatherAPI
+	if viacep.Rate != 2.5 || viacep.Mode != "queue" || weatherapi.Rate != 0 || weatherapi.Mode != "fail-fast" {
+		t.Fatalf("unexpected rate limits: %+v", cfg.RateLimits)
+	}
+
+	t.Setenv("WEATHERAPI_RATE_BURST", "0")
+	cfg = DefaultServiceB()
+	if err := Load(&cfg, ""); err == nil || !strings.Contains(err.Error(), "rate_limits.weatherapi.burst:") {
+		t.Fatalf("expected burst validation error, got %v", err)
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "key")
+	t.Setenv("WEATHER_CACHE_TTL", "1m")
+	t.Setenv("VIACEP_RATE_LIMIT", "2.5")
+	t.Setenv("LEGACY_KELVIN", "")
+
+	want := map[string]string{
+		"weather_api_key":         "WEATHER_API_KEY",
+		"cache.weather_ttl":       "WEATHER_CACHE_TTL",
+		"rate_limits.viacep.rate": "VIACEP_RATE_LIMIT",
+	}
+	if got := EnvOverrides(DefaultServiceB()); !maps.Equal(got, want) {
+		t.Fatalf("EnvOverrides = %v, want %v", got, want)
+	}
+}
+
+func TestLoad_JWT(t *testing.T) {
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("JWT_ENABLED", "true")
+	t.Setenv("JWT_JWKS_URL", "https://idp.example.com/.well-known/jwks.json")
+	t.Setenv("JWT_ISSUER", "https://idp.example.com")
+	t.Setenv("JWT_TIER", "gold")
+
+	// Com tokens, o arquivo de chaves é opcional; os escopos e o plano são validados
+	cfg := DefaultServiceA()
+	err := Load(&cfg, "")
+	for _, want := range []string{"auth.jwt.audience:", "auth.jwt.scopes:", "auth.jwt.tier:"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+	if err != nil && strings.Contains(err.Error(), "auth.keys_file:") {
+		t.Errorf("expected keys_file to be optional with jwt, got %v", err)
+	}
+
+	t.Setenv("JWT_TIER", "premium")
+	path := writeFile(t, "service-a.yaml", `
+auth:
+  jwt:
+    audience: cep-weather
+    scopes:
+      weather:read: ["/weather", "/weather/{cep}"]
+`)
+	cfg = DefaultServiceA()
+	if err := Load(&cfg, path); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if jwt := cfg.Auth.JWT; jwt.Audience != "cep-weather" || len(jwt.Scopes["weather:read"]) != 2 || jwt.CacheTTL != Duration(10*time.Minute) {
+		t.Fatalf("unexpected jwt config: %+v", jwt)
+	}
+}
+
+func TestLoad_TLS(t *testing.T) {
+	t.Setenv("SERVICE_B_TLS_ENABLED", "true")
+	t.Setenv("SERVICE_B_TLS_CERT_FILE", "/certs/service-a.pem")
+	t.Setenv("TLS_ENABLED", "true")
+
+	// No cliente, o certificado exige a chave e a URL do Serviço B exige https
+	cfg := DefaultServiceA()
+	err := Load(&cfg, "")
+	for _, want := range []string{"service_b.tls: cert_file and key_file", "service_b.url: must use https"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+
+	t.Setenv("SERVICE_B_TLS_KEY_FILE", "/certs/service-a-key.pem")
+	t.Setenv("SERVICE_B_URL", "https://service-b:8081")
+	cfg = DefaultServiceA()
+	if err := Load(&cfg, ""); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// As variáveis TLS_* são do Serviço B; no Serviço A valem as SERVICE_B_TLS_*
+	if tls := cfg.ServiceB.TLS; !tls.Enabled || tls.KeyFile != "/certs/service-a-key.pem" || tls.ReloadInterval != Duration(time.Minute) {
+		t.Fatalf("unexpected client tls config: %+v", tls)
+	}
+
+	// O servidor exige o próprio certificado
+	t.Setenv("WEATHER_API_KEY", "key")
+	b := DefaultServiceB()
+	if err := Load(&b, ""); err == nil || !strings.Contains(err.Error(), "tls: cert_file and key_file are required") {
+		t.Fatalf("expected server certificate error, got %v", err)
+	}
+}
+
+func TestFormat_RedactsSecrets(t *testing.T) {
+	cfg := DefaultServiceB()
+	cfg.WeatherAPIKey = "super-secret"
+	cfg.WeatherAPIKeys = []Secret{"second-secret"}
+
+	out := Format(cfg)
+	if strings.Contains(out, "super-secret") || strings.Contains(out, "second-secret") || !strings.Contains(out, "weather_api_key: '[REDACTED]'") {
+		t.Fatalf("expected redacted secret, got:\n%s", out)
+	}
+	if !strings.Contains(out, "weather_ttl: 5m0s") {
+		t.Fatalf("expected durations as text, got:\n%s", out)
+	}
+	if s := cfg.WeatherAPIKey.String(); s != "[REDACTED]" {
+		t.Fatalf("expected redacted String(), got %q", s)
+	}
+}
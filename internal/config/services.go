@@ -0,0 +1,528 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultZipkinURL é o endereço padrão do Zipkin no docker-compose
+const DefaultZipkinURL = "http://zipkin:9411/api/v2/spans"
+
+// ServiceA reúne a configuração do Serviço A
+type ServiceA struct {
+	Port              string       `yaml:"port" json:"port" env:"PORT"`                                           // Porta do servidor HTTP
+	ZipkinURL         string       `yaml:"zipkin_url" json:"zipkin_url" env:"ZIPKIN_URL"`                         // Endpoint do Zipkin para os traces
+	LegacyErrors      bool         `yaml:"legacy_errors" json:"legacy_errors" env:"LEGACY_ERRORS"`                // Erros em texto puro (contrato original)
+	StreamConcurrency int          `yaml:"stream_concurrency" json:"stream_concurrency" env:"STREAM_CONCURRENCY"` // Consultas paralelas nos lotes em streaming
+	SamplerRatio      float64      `yaml:"sampler_ratio" json:"sampler_ratio" env:"TRACE_SAMPLER_RATIO"`          // Proporção dos traces amostrados (0 a 1)
+	Propagators       []string     `yaml:"propagators" json:"propagators" env:"OTEL_PROPAGATORS"`                 // Formatos do contexto propagado (ver telemetry.SetPropagators)
+	APIKeyHeader      string       `yaml:"api_key_header" json:"api_key_header" env:"API_KEY_HEADER"`             // Cabeçalho com a chave de API do cliente
+	ServiceB          Upstream     `yaml:"service_b" json:"service_b"`                                            // Comunicação com o Serviço B
+	Auth              Auth         `yaml:"auth" json:"auth"`                                                      // Autenticação dos clientes por chave de API
+	RateLimit         InboundLimit `yaml:"rate_limit" json:"rate_limit"`                                          // Cotas de requisições por cliente
+	Metrics           Metrics      `yaml:"metrics" json:"metrics"`                                                // Exposição das métricas
+}
+
+// Metrics configura a exposição das métricas do OpenTelemetry no formato do
+// Prometheus (GET /metrics), em uma porta separada da API
+type Metrics struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" env:"METRICS_ENABLED"` // Expõe as métricas
+	Port    string `yaml:"port" json:"port" env:"METRICS_PORT"`          // Porta do servidor de métricas
+}
+
+// validate verifica a porta apenas quando as métricas estão ativas
+func (m Metrics) validate(name string) error {
+	if !m.Enabled {
+		return nil
+	}
+	return validatePort(name+".port", m.Port)
+}
+
+// Upstream reúne a configuração da comunicação do Serviço A com o Serviço B
+type Upstream struct {
+	URL          string   `yaml:"url" json:"url" env:"SERVICE_B_URL"`                               // URL do endpoint de clima (HTTP)
+	Protocol     string   `yaml:"protocol" json:"protocol" env:"SERVICE_B_PROTOCOL"`                // "http" ou "grpc"
+	GRPCAddr     string   `yaml:"grpc_addr" json:"grpc_addr" env:"SERVICE_B_GRPC_ADDR"`             // Endereço do WeatherService (gRPC)
+	Timeout      Duration `yaml:"timeout" json:"timeout" env:"SERVICE_B_TIMEOUT"`                   // Prazo das consultas individuais (0 desativa)
+	BatchTimeout Duration `yaml:"batch_timeout" json:"batch_timeout" env:"SERVICE_B_BATCH_TIMEOUT"` // Prazo das consultas em lote (0 desativa)
+	TLS          TLS      `yaml:"tls" json:"tls" envprefix:"SERVICE_B_"`                            // TLS nas chamadas ao Serviço B (HTTP e gRPC)
+}
+
+// TLS configura o TLS da comunicação entre os serviços (ver certs.Reloader)
+//
+// No servidor (Serviço B), cert_file e key_file são obrigatórios e ca_file
+// ativa o TLS mútuo, exigindo certificados de cliente emitidos pela CA. No
+// cliente (Serviço A), ca_file substitui as raízes do sistema na verificação
+// do servidor e cert_file/key_file são o certificado apresentado no TLS mútuo.
+// Os arquivos são verificados a cada reload_interval e os certificados
+// renovados valem para as novas conexões sem reinício.
+type TLS struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled" env:"TLS_ENABLED"`                         // Ativa o TLS
+	CertFile       string   `yaml:"cert_file" json:"cert_file" env:"TLS_CERT_FILE"`                   // Certificado do serviço (PEM)
+	KeyFile        string   `yaml:"key_file" json:"key_file" env:"TLS_KEY_FILE"`                      // Chave privada do certificado (PEM)
+	CAFile         string   `yaml:"ca_file" json:"ca_file" env:"TLS_CA_FILE"`                         // CA dos certificados do outro lado (PEM)
+	ServerName     string   `yaml:"server_name" json:"server_name" env:"TLS_SERVER_NAME"`             // Cliente: nome esperado no certificado do servidor ("" usa o host)
+	ReloadInterval Duration `yaml:"reload_interval" json:"reload_interval" env:"TLS_RELOAD_INTERVAL"` // Intervalo de verificação dos arquivos (0 desativa)
+}
+
+// validate verifica os arquivos do TLS; o servidor exige o próprio certificado
+func (t TLS) validate(name string, server bool) error {
+	if !t.Enabled {
+		return nil
+	}
+	var pair error
+	switch {
+	case server && (t.CertFile == "" || t.KeyFile == ""):
+		pair = fmt.Errorf("%s: cert_file and key_file are required", name)
+	case (t.CertFile == "") != (t.KeyFile == ""):
+		pair = fmt.Errorf("%s: cert_file and key_file must be set together", name)
+	}
+	return errors.Join(pair, validateDuration(name+".reload_interval", t.ReloadInterval))
+}
+
+// validateScheme verifica se a URL do Serviço B usa https quando o TLS está
+// ativo no modo HTTP; com http://, o TLS configurado seria ignorado
+func (u Upstream) validateScheme() error {
+	if !u.TLS.Enabled || u.Protocol != "http" || strings.HasPrefix(u.URL, "https://") {
+		return nil
+	}
+	return fmt.Errorf("service_b.url: must use https when service_b.tls is enabled, got %q", u.URL)
+}
+
+// Auth reúne a autenticação dos clientes do Serviço A
+//
+// Quando ativada, toda requisição aos endpoints de clima deve informar uma
+// credencial: a chave de API, no cabeçalho api_key_header, presente no
+// arquivo keys_file (ver auth.KeyStore), ou um token de acesso (JWT) no
+// cabeçalho Authorization, quando jwt está ativo. O arquivo de chaves é
+// verificado a cada reload_interval e as alterações valem sem reinício.
+type Auth struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled" env:"AUTH_ENABLED"`                             // Exige a credencial do cliente
+	KeysFile       string   `yaml:"keys_file" json:"keys_file" env:"API_KEYS_FILE"`                        // Arquivo com as chaves e os metadados dos clientes
+	ReloadInterval Duration `yaml:"reload_interval" json:"reload_interval" env:"API_KEYS_RELOAD_INTERVAL"` // Intervalo de verificação do arquivo de chaves (0 desativa)
+	JWT            JWT      `yaml:"jwt" json:"jwt" envprefix:"JWT_"`                                       // Tokens de acesso do provedor de identidade
+}
+
+// JWT configura a validação dos tokens de acesso (ver auth.Verifier)
+// As variáveis de ambiente recebem o prefixo JWT_ (ex: JWT_ISSUER)
+type JWT struct {
+	Enabled  bool                `yaml:"enabled" json:"enabled" env:"ENABLED"`            // Aceita tokens no cabeçalho Authorization: Bearer
+	JWKSFile string              `yaml:"jwks_file" json:"jwks_file" env:"JWKS_FILE"`      // Arquivo com as chaves públicas do provedor (JWKS)
+	JWKSURL  string              `yaml:"jwks_url" json:"jwks_url" env:"JWKS_URL"`         // URL das chaves públicas do provedor (JWKS)
+	CacheTTL Duration            `yaml:"cache_ttl" json:"cache_ttl" env:"JWKS_CACHE_TTL"` // Validade do cache do JWKS
+	Issuer   string              `yaml:"issuer" json:"issuer" env:"ISSUER"`               // Emissor esperado (claim iss)
+	Audience string              `yaml:"audience" json:"audience" env:"AUDIENCE"`         // Audiência esperada (claim aud)
+	Leeway   Duration            `yaml:"leeway" json:"leeway" env:"LEEWAY"`               // Tolerância para diferenças de relógio
+	Tier     string              `yaml:"tier" json:"tier" env:"TIER"`                     // Plano das cotas dos clientes com token ("" usa rate_limit.key_tier)
+	Scopes   map[string][]string `yaml:"scopes" json:"scopes"`                            // Endpoints liberados por escopo
+}
+
+// validate verifica as credenciais aceitas quando a autenticação está ativa
+func (a Auth) validate(name string) error {
+	if !a.Enabled {
+		return nil
+	}
+	var keys error
+	if !a.JWT.Enabled {
+		keys = validateRequired(name+".keys_file", a.KeysFile)
+	}
+	return errors.Join(
+		keys,
+		validateDuration(name+".reload_interval", a.ReloadInterval),
+		a.JWT.validate(name+".jwt"),
+	)
+}
+
+// validate verifica a origem do JWKS, as claims esperadas e os escopos
+func (j JWT) validate(name string) error {
+	if !j.Enabled {
+		return nil
+	}
+	var source, ttl, scopes error
+	if (j.JWKSFile == "") == (j.JWKSURL == "") {
+		source = fmt.Errorf("%s: exactly one of jwks_file or jwks_url is required", name)
+	} else if j.JWKSURL != "" {
+		source = validateURL(name+".jwks_url", j.JWKSURL)
+	}
+	if j.CacheTTL <= 0 {
+		ttl = fmt.Errorf("%s.cache_ttl: must be positive, got %s", name, time.Duration(j.CacheTTL))
+	}
+	if len(j.Scopes) == 0 {
+		scopes = fmt.Errorf("%s.scopes: at least one scope must grant endpoints", name)
+	}
+	return errors.Join(
+		source,
+		ttl,
+		validateRequired(name+".issuer", j.Issuer),
+		validateRequired(name+".audience", j.Audience),
+		validateDuration(name+".leeway", j.Leeway),
+		scopes,
+	)
+}
+
+// InboundLimit reúne as cotas de requisições dos clientes do Serviço A
+//
+// Clientes que informam a chave de API (cabeçalho api_key_header) são
+// identificados pela chave e usam o plano do cliente no arquivo de chaves ou,
+// na falta dele, o plano key_tier; os demais são identificados pelo IP e usam
+// o plano anonymous_tier. O IP vem de X-Forwarded-For apenas quando a conexão
+// parte de um proxy confiável.
+type InboundLimit struct {
+	Enabled        bool            `yaml:"enabled" json:"enabled" env:"RATE_LIMIT_ENABLED"`                      // Ativa as cotas
+	TrustedProxies []string        `yaml:"trusted_proxies" json:"trusted_proxies" env:"TRUSTED_PROXIES"`         // IPs ou faixas CIDR dos proxies confiáveis
+	AnonymousTier  string          `yaml:"anonymous_tier" json:"anonymous_tier" env:"RATE_LIMIT_ANONYMOUS_TIER"` // Plano dos clientes sem chave de API
+	KeyTier        string          `yaml:"key_tier" json:"key_tier" env:"RATE_LIMIT_KEY_TIER"`                   // Plano dos clientes com chave de API
+	Tiers          map[string]Tier `yaml:"tiers" json:"tiers"`                                                   // Cota de cada plano, pelo nome
+}
+
+// Tier é a cota de requisições de um plano: no máximo Requests a cada Window
+type Tier struct {
+	Requests int      `yaml:"requests" json:"requests"`
+	Window   Duration `yaml:"window" json:"window"`
+}
+
+// DefaultServiceA retorna a configuração padrão do Serviço A, adequada ao
+// desenvolvimento local
+func DefaultServiceA() ServiceA {
+	return ServiceA{
+		Port:              "8080",
+		ZipkinURL:         DefaultZipkinURL,
+		StreamConcurrency: 8,
+		SamplerRatio:      1,
+		Propagators:       []string{"tracecontext", "baggage"},
+		APIKeyHeader:      "X-API-Key",
+		Metrics:           Metrics{Enabled: true, Port: "9464"},
+		Auth: Auth{
+			ReloadInterval: Duration(10 * time.Second),
+			JWT:            JWT{CacheTTL: Duration(10 * time.Minute), Leeway: Duration(30 * time.Second)},
+		},
+		RateLimit: InboundLimit{
+			Enabled:       true,
+			AnonymousTier: "anonymous",
+			KeyTier:       "standard",
+			Tiers: map[string]Tier{
+				"anonymous": {Requests: 60, Window: Duration(time.Minute)},
+				"standard":  {Requests: 600, Window: Duration(time.Minute)},
+				"premium":   {Requests: 6000, Window: Duration(time.Minute)},
+			},
+		},
+		ServiceB: Upstream{
+			URL:          "http://localhost:8081/weather",
+			Protocol:     "http",
+			GRPCAddr:     "localhost:50051",
+			Timeout:      Duration(10 * time.Second),
+			BatchTimeout: Duration(60 * time.Second),
+			TLS:          TLS{ReloadInterval: Duration(time.Minute)},
+		},
+	}
+}
+
+// Validate verifica todos os campos e reúne os problemas encontrados
+func (c ServiceA) Validate() error {
+	return errors.Join(
+		validatePort("port", c.Port),
+		validateURL("zipkin_url", c.ZipkinURL),
+		validateMin("stream_concurrency", c.StreamConcurrency, 1),
+		validateRatio("sampler_ratio", c.SamplerRatio),
+		validatePropagators("propagators", c.Propagators),
+		validateRequired("api_key_header", c.APIKeyHeader),
+		validateURL("service_b.url", c.ServiceB.URL),
+		validateOneOf("service_b.protocol", c.ServiceB.Protocol, "http", "grpc"),
+		validateRequired("service_b.grpc_addr", c.ServiceB.GRPCAddr),
+		validateDuration("service_b.timeout", c.ServiceB.Timeout),
+		validateDuration("service_b.batch_timeout", c.ServiceB.BatchTimeout),
+		c.ServiceB.TLS.validate("service_b.tls", false),
+		c.ServiceB.validateScheme(),
+		c.Auth.validate("auth"),
+		validateOptionalTier("auth.jwt.tier", c.Auth.JWT.Tier, c.RateLimit.Tiers),
+		c.RateLimit.validate("rate_limit"),
+		c.Metrics.validate("metrics"),
+	)
+}
+
+// validate verifica os planos e os proxies confiáveis
+func (l InboundLimit) validate(name string) error {
+	errs := []error{
+		validateTier(name+".anonymous_tier", l.AnonymousTier, l.Tiers),
+		validateTier(name+".key_tier", l.KeyTier, l.Tiers),
+	}
+	for tier, q := range l.Tiers {
+		errs = append(errs, validateMin(name+".tiers."+tier+".requests", q.Requests, 1))
+		if q.Window <= 0 {
+			errs = append(errs, fmt.Errorf("%s.tiers.%s.window: must be positive, got %s", name, tier, time.Duration(q.Window)))
+		}
+	}
+	for _, p := range l.TrustedProxies {
+		if _, err := ParseNetwork(p); err != nil {
+			errs = append(errs, fmt.Errorf("%s.trusted_proxies: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseNetwork interpreta um IP ("10.0.0.1") ou faixa CIDR ("10.0.0.0/8")
+func ParseNetwork(raw string) (*net.IPNet, error) {
+	if _, n, err := net.ParseCIDR(raw); err == nil {
+		return n, nil
+	}
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP or CIDR %q", raw)
+	}
+	bits := 8 * len(ip.To16())
+	if ip.To4() != nil {
+		ip, bits = ip.To4(), 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// validateTier verifica se o plano existe entre os configurados
+func validateTier(name, tier string, tiers map[string]Tier) error {
+	if _, ok := tiers[tier]; !ok {
+		return fmt.Errorf("%s: unknown tier %q", name, tier)
+	}
+	return nil
+}
+
+// validateOptionalTier verifica o plano, quando informado
+func validateOptionalTier(name, tier string, tiers map[string]Tier) error {
+	if tier == "" {
+		return nil
+	}
+	return validateTier(name, tier, tiers)
+}
+
+// ServiceB reúne a configuração do Serviço B
+type ServiceB struct {
+	Port                string     `yaml:"port" json:"port" env:"PORT"`                                                    // Porta do servidor HTTP
+	GRPCPort            string     `yaml:"grpc_port" json:"grpc_port" env:"GRPC_PORT"`                                     // Porta do servidor gRPC
+	ZipkinURL           string     `yaml:"zipkin_url" json:"zipkin_url" env:"ZIPKIN_URL"`                                  // Endpoint do Zipkin para os traces
+	LegacyErrors        bool       `yaml:"legacy_errors" json:"legacy_errors" env:"LEGACY_ERRORS"`                         // Erros em texto puro (contrato original)
+	WeatherAPIKey       Secret     `yaml:"weather_api_key" json:"weather_api_key" env:"WEATHER_API_KEY"`                   // Chave da WeatherAPI
+	WeatherAPIKeys      []Secret   `yaml:"weather_api_keys" json:"weather_api_keys" env:"WEATHER_API_KEYS"`                // Chaves adicionais, usadas em conjunto com weather_api_key
+	KeyStrategy         string     `yaml:"key_strategy" json:"key_strategy" env:"WEATHER_API_KEY_STRATEGY"`                // Escolha da chave: "round-robin" ou "least-used"
+	KeyQuarantine       Duration   `yaml:"key_quarantine" json:"key_quarantine" env:"WEATHER_API_KEY_QUARANTINE"`          // Quarentena das chaves recusadas (401/403)
+	BatchConcurrency    int        `yaml:"batch_concurrency" json:"batch_concurrency" env:"BATCH_CONCURRENCY"`             // CEPs consultados em paralelo em um lote
+	AirQualityProviders []string   `yaml:"air_quality_providers" json:"air_quality_providers" env:"AIR_QUALITY_PROVIDERS"` // Provedores de qualidade do ar, na ordem de consulta
+	SamplerRatio        float64    `yaml:"sampler_ratio" json:"sampler_ratio" env:"TRACE_SAMPLER_RATIO"`                   // Proporção dos traces amostrados (0 a 1)
+	Propagators         []string   `yaml:"propagators" json:"propagators" env:"OTEL_PROPAGATORS"`                          // Formatos do contexto propagado (ver telemetry.SetPropagators)
+	BaggageAttributes   []string   `yaml:"baggage_attributes" json:"baggage_attributes" env:"BAGGAGE_SPAN_ATTRIBUTES"`     // Membros do baggage copiados para os spans
+	ReloadInterval      Duration   `yaml:"reload_interval" json:"reload_interval" env:"CONFIG_RELOAD_INTERVAL"`            // Intervalo de verificação do arquivo de configuração (0 desativa)
+	Cache               Cache      `yaml:"cache" json:"cache"`                                                             // Validade dos resultados em cache
+	RateLimits          RateLimits `yaml:"rate_limits" json:"rate_limits"`                                                 // Limite de chamadas às APIs externas
+	Units               Units      `yaml:"units" json:"units"`                                                             // Conversões de unidade
+	TLS                 TLS        `yaml:"tls" json:"tls"`                                                                 // TLS dos servidores HTTP e gRPC
+	Metrics             Metrics    `yaml:"metrics" json:"metrics"`                                                         // Exposição das métricas
+}
+
+// Cache reúne a validade dos resultados das APIs externas em cache
+type Cache struct {
+	LocationTTL       Duration `yaml:"location_ttl" json:"location_ttl" env:"LOCATION_CACHE_TTL"`                      // Localizações por CEP (ViaCEP)
+	WeatherTTL        Duration `yaml:"weather_ttl" json:"weather_ttl" env:"WEATHER_CACHE_TTL"`                         // Condições, previsões, qualidade do ar e alertas (WeatherAPI)
+	MaxEntries        int      `yaml:"max_entries" json:"max_entries" env:"CACHE_MAX_ENTRIES"`                         // Limite de entradas de cada cache (0 = sem limite)
+	HistoryMaxEntries int      `yaml:"history_max_entries" json:"history_max_entries" env:"HISTORY_CACHE_MAX_ENTRIES"` // Limite de dias do histórico, que não expira (0 = sem limite)
+}
+
+// RateLimits reúne os limites de taxa das chamadas a cada API externa
+type RateLimits struct {
+	ViaCEP     RateLimit `yaml:"viacep" json:"viacep" envprefix:"VIACEP_"`             // ViaCEP (uso justo)
+	WeatherAPI RateLimit `yaml:"weatherapi" json:"weatherapi" envprefix:"WEATHERAPI_"` // WeatherAPI (cobrança por chamada)
+}
+
+// RateLimit configura o limitador de taxa (token bucket) de uma API externa
+// As variáveis de ambiente recebem o prefixo da API (ex: VIACEP_RATE_LIMIT)
+type RateLimit struct {
+	Rate  float64 `yaml:"rate" json:"rate" env:"RATE_LIMIT"`   // Chamadas por segundo (0 desativa o limite)
+	Burst int     `yaml:"burst" json:"burst" env:"RATE_BURST"` // Chamadas permitidas de uma vez, antes de aplicar a taxa
+	Mode  string  `yaml:"mode" json:"mode" env:"RATE_MODE"`    // Sem token disponível: "queue" (aguarda até o prazo) ou "fail-fast"
+}
+
+// validate verifica o limite de taxa da API indicada por name
+func (r RateLimit) validate(name string) error {
+	var rate error
+	if r.Rate < 0 {
+		rate = fmt.Errorf("%s.rate: must not be negative, got %v", name, r.Rate)
+	}
+	return errors.Join(
+		rate,
+		validateMin(name+".burst", r.Burst, 1),
+		validateOneOf(name+".mode", r.Mode, "queue", "fail-fast"),
+	)
+}
+
+// Units reúne as opções das conversões de unidade (ver pacote units)
+type Units struct {
+	Precision    int  `yaml:"precision" json:"precision" env:"UNIT_PRECISION"`        // Casas decimais (negativo desativa o arredondamento)
+	LegacyKelvin bool `yaml:"legacy_kelvin" json:"legacy_kelvin" env:"LEGACY_KELVIN"` // Fórmula legada K = C + 273
+}
+
+// DefaultServiceB retorna a configuração padrão do Serviço B
+// A chave da WeatherAPI não tem valor padrão: ao menos uma precisa ser
+// informada, em weather_api_key ou weather_api_keys
+func DefaultServiceB() ServiceB {
+	return ServiceB{
+		Port:             "8081",
+		GRPCPort:         "50051",
+		ZipkinURL:        DefaultZipkinURL,
+		KeyStrategy:      "round-robin",
+		KeyQuarantine:    Duration(time.Hour),
+		BatchConcurrency: 8,
+		// A Open-Meteo dispensa chave e cobre falhas ou quarentena das chaves da WeatherAPI
+		AirQualityProviders: []string{"weatherapi", "open-meteo"},
+		SamplerRatio:        1,
+		Propagators:         []string{"tracecontext", "baggage"},
+		// Identidade do cliente e da requisição, definida no baggage pelo Serviço A
+		BaggageAttributes: []string{"tenant.id", "client.name", "client.tier", "request.id"},
+		ReloadInterval:    Duration(10 * time.Second),
+		Cache: Cache{
+			// Localizações mudam raramente; temperaturas são atualizadas a cada 15 minutos pela WeatherAPI
+			LocationTTL: Duration(24 * time.Hour),
+			WeatherTTL:  Duration(5 * time.Minute),
+			MaxEntries:  10000,
+			// Cada dia ocupa poucos KB; o limite só descarta os dias menos consultados
+			HistoryMaxEntries: 50000,
+		},
+		RateLimits: RateLimits{
+			// A ViaCEP não publica o limite de uso justo; 10 chamadas por
+			// segundo atendem lotes sem aproximar o serviço do bloqueio
+			ViaCEP: RateLimit{Rate: 10, Burst: 20, Mode: "queue"},
+			// A WeatherAPI não limita a taxa, apenas a cota mensal (ver weather_api_keys)
+			WeatherAPI: RateLimit{Rate: 0, Burst: 20, Mode: "queue"},
+		},
+		Units:   Units{Precision: 2},
+		Metrics: Metrics{Enabled: true, Port: "9464"},
+		TLS:     TLS{ReloadInterval: Duration(time.Minute)},
+	}
+}
+
+// Validate verifica todos os campos e reúne os problemas encontrados
+func (c ServiceB) Validate() error {
+	return errors.Join(
+		validatePort("port", c.Port),
+		validatePort("grpc_port", c.GRPCPort),
+		validateURL("zipkin_url", c.ZipkinURL),
+		validateRequired("weather_api_key", strings.Join(c.APIKeys(), "")),
+		validateOneOf("key_strategy", c.KeyStrategy, "round-robin", "least-used"),
+		validateDuration("key_quarantine", c.KeyQuarantine),
+		validateMin("batch_concurrency", c.BatchConcurrency, 1),
+		validateProviders("air_quality_providers", c.AirQualityProviders, "weatherapi", "open-meteo"),
+		validatePropagators("propagators", c.Propagators),
+		validateRatio("sampler_ratio", c.SamplerRatio),
+		validateDuration("reload_interval", c.ReloadInterval),
+		validateDuration("cache.location_ttl", c.Cache.LocationTTL),
+		validateDuration("cache.weather_ttl", c.Cache.WeatherTTL),
+		validateMin("cache.max_entries", c.Cache.MaxEntries, 0),
+		validateMin("cache.history_max_entries", c.Cache.HistoryMaxEntries, 0),
+		c.RateLimits.ViaCEP.validate("rate_limits.viacep"),
+		c.RateLimits.WeatherAPI.validate("rate_limits.weatherapi"),
+		c.TLS.validate("tls", true),
+		c.Metrics.validate("metrics"),
+	)
+}
+
+// APIKeys retorna todas as chaves da WeatherAPI configuradas:
+// weather_api_key seguida de weather_api_keys, sem valores vazios
+func (c ServiceB) APIKeys() []string {
+	var keys []string
+	for _, k := range append([]Secret{c.WeatherAPIKey}, c.WeatherAPIKeys...) {
+		if k != "" {
+			keys = append(keys, k.Value())
+		}
+	}
+	return keys
+}
+
+// validatePort verifica se a porta é um número entre 1 e 65535
+func validatePort(name, port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s: must be a port number between 1 and 65535, got %q", name, port)
+	}
+	return nil
+}
+
+// validateURL verifica se o valor é uma URL absoluta http(s)
+func validateURL(name, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s: must be an absolute http(s) URL, got %q", name, raw)
+	}
+	return nil
+}
+
+// validateRequired verifica se o valor foi informado
+func validateRequired(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s: is required", name)
+	}
+	return nil
+}
+
+// validateMin verifica se o valor é maior ou igual ao mínimo
+func validateMin(name string, value, min int) error {
+	if value < min {
+		return fmt.Errorf("%s: must be at least %d, got %d", name, min, value)
+	}
+	return nil
+}
+
+// validateRatio verifica se a proporção está entre 0 e 1
+func validateRatio(name string, value float64) error {
+	if value < 0 || value > 1 {
+		return fmt.Errorf("%s: must be between 0 and 1, got %v", name, value)
+	}
+	return nil
+}
+
+// validateDuration verifica se a duração não é negativa (0 desativa o limite ou a expiração)
+func validateDuration(name string, d Duration) error {
+	if d < 0 {
+		return fmt.Errorf("%s: must not be negative, got %s", name, time.Duration(d))
+	}
+	return nil
+}
+
+// validatePropagators verifica os nomes dos propagadores (ver telemetry.SetPropagators)
+func validatePropagators(name string, names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("%s: at least one propagator is required", name)
+	}
+	var errs []error
+	for _, n := range names {
+		errs = append(errs, validateOneOf(name, n, "tracecontext", "baggage", "b3", "b3multi"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateProviders verifica a lista de provedores: ao menos um, todos
+// conhecidos e sem repetição
+func validateProviders(name string, names []string, known ...string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("%s: at least one provider is required", name)
+	}
+	var errs []error
+	seen := make(map[string]bool, len(names))
+	for _, n := range names {
+		if seen[n] {
+			errs = append(errs, fmt.Errorf("%s: duplicate provider %q", name, n))
+		}
+		seen[n] = true
+		errs = append(errs, validateOneOf(name, n, known...))
+	}
+	return errors.Join(errs...)
+}
+
+// validateOneOf verifica se o valor está entre as opções permitidas
+func validateOneOf(name, value string, options ...string) error {
+	for _, o := range options {
+		if value == o {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: must be one of %v, got %q", name, options, value)
+}
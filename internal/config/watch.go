@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// stamp identifica uma versão do arquivo de configuração
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile retorna a versão atual do arquivo (zero se ele não puder ser lido)
+func statFile(path string) stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// Watch verifica periodicamente o arquivo de configuração e chama onChange
+// sempre que ele for alterado, até que ctx seja cancelado
+//
+// A alteração é detectada pela data de modificação e pelo tamanho do arquivo.
+// A verificação periódica dispensa notificações do sistema de arquivos, que
+// não funcionam em todos os volumes (ex: ConfigMaps do Kubernetes, em que o
+// arquivo é substituído por meio de links simbólicos). Enquanto o arquivo
+// estiver ausente, nenhuma alteração é informada.
+//
+// Parâmetros:
+//   - ctx: Contexto que encerra a verificação
+//   - path: Caminho do arquivo de configuração
+//   - interval: Intervalo entre as verificações
+//   - onChange: Função chamada a cada alteração detectada
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last := statFile(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := statFile(path)
+			if current == (stamp{}) || current == last {
+				continue
+			}
+			last = current
+			onChange()
+		}
+	}
+}
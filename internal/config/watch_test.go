@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "service-b.yaml", "port: \"8081\"\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+	go Watch(ctx, path, 10*time.Millisecond, func() { changes <- struct{}{} })
+
+	// Sem alterações, onChange não é chamada
+	select {
+	case <-changes:
+		t.Fatalf("unexpected change notification")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(path, []byte("port: \"9090\"\nbatch_concurrency: 4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected change notification after the file was rewritten")
+	}
+}
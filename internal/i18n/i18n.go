@@ -0,0 +1,168 @@
+// Pacote i18n localiza as mensagens expostas pela API (títulos e detalhes de
+// erro, descrição das condições do tempo) conforme o cabeçalho Accept-Language.
+//
+// O inglês é o idioma de origem: as mensagens são escritas em inglês no código
+// e usadas como chave nos catálogos dos demais idiomas, embutidos no binário
+// (catalogs/*.json). Mensagens sem tradução são devolvidas sem alteração, e os
+// códigos de erro legíveis por máquina nunca são traduzidos.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Lang é um idioma suportado, identificado pela tag BCP 47 (ex: "pt-BR")
+type Lang string
+
+// Idiomas suportados
+const (
+	English    Lang = "en"    // Idioma de origem das mensagens
+	Portuguese Lang = "pt-BR" // Português do Brasil
+	Spanish    Lang = "es"    // Espanhol
+)
+
+// Default é o idioma usado quando o cliente não informa Accept-Language ou
+// nenhum dos idiomas aceitos é suportado; mantém o comportamento original da API
+const Default = English
+
+// Supported lista os idiomas suportados, na ordem de preferência em caso de empate
+var Supported = []Lang{English, Portuguese, Spanish}
+
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+// catalogs mapeia cada idioma para suas traduções, indexadas pela mensagem em
+// inglês normalizada (ver key)
+var catalogs = mustLoad()
+
+// mustLoad carrega os catálogos embutidos; um catálogo inválido é erro de programação
+func mustLoad() map[Lang]map[string]string {
+	files, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+	loaded := make(map[Lang]map[string]string, len(files))
+	for _, f := range files {
+		lang := Lang(strings.TrimSuffix(f.Name(), ".json"))
+		data, err := catalogFS.ReadFile(path.Join("catalogs", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: catálogo %s inválido: %v", f.Name(), err))
+		}
+		catalog := make(map[string]string, len(messages))
+		for msg, translation := range messages {
+			catalog[key(msg)] = translation
+		}
+		loaded[lang] = catalog
+	}
+	return loaded
+}
+
+// key normaliza a mensagem usada como chave nos catálogos
+// A WeatherAPI, por exemplo, varia a capitalização e inclui espaços extras
+// na descrição das condições ("Patchy rain possible ")
+func key(msg string) string {
+	return strings.ToLower(strings.TrimSpace(msg))
+}
+
+// T traduz a mensagem em inglês para o idioma informado
+// Devolve a mensagem original quando não há tradução
+func T(lang Lang, msg string) string {
+	if msg == "" {
+		return msg
+	}
+	if translation, ok := catalogs[lang][key(msg)]; ok {
+		return translation
+	}
+	return msg
+}
+
+// Negotiate escolhe o idioma da resposta a partir do cabeçalho Accept-Language
+//
+// Considera os valores de qualidade (q); em caso de empate, vence o que
+// aparece primeiro no cabeçalho. Variantes regionais são aceitas pelo idioma
+// principal (ex: "pt-PT" e "pt" resultam em pt-BR, "es-AR" em es).
+func Negotiate(header string) Lang {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		q := 1.0
+		if raw, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		lang, ok := match(tag)
+		if ok && q > 0 && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// match encontra o idioma suportado correspondente à tag informada
+func match(tag string) (Lang, bool) {
+	if tag == "*" {
+		return Default, true
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	for _, lang := range Supported {
+		if strings.EqualFold(tag, string(lang)) {
+			return lang, true
+		}
+	}
+	for _, lang := range Supported {
+		p, _, _ := strings.Cut(string(lang), "-")
+		if strings.EqualFold(primary, p) {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// langKey é a chave do idioma negociado no contexto da requisição
+type langKey struct{}
+
+// WithLang retorna um contexto com o idioma informado
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// FromContext retorna o idioma presente no contexto, ou Default quando ausente
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(langKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
+
+// FromRequest retorna o idioma da requisição: o do contexto, quando definido
+// por Middleware, ou o negociado a partir do cabeçalho Accept-Language
+func FromRequest(r *http.Request) Lang {
+	if lang, ok := r.Context().Value(langKey{}).(Lang); ok {
+		return lang
+	}
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// Middleware negocia o idioma de cada requisição e o disponibiliza no contexto
+// Assim, mensagens criadas a partir do contexto (ex: erros de itens de um lote)
+// seguem o idioma pedido pelo cliente
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := Negotiate(r.Header.Get("Accept-Language"))
+		next.ServeHTTP(w, r.WithContext(WithLang(r.Context(), lang)))
+	})
+}
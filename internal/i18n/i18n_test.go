@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		header string
+		want   Lang
+	}{
+		{"", English},
+		{"pt-BR", Portuguese},
+		{"pt-BR,pt;q=0.9,en;q=0.8", Portuguese},
+		{"pt-PT", Portuguese},
+		{"es-AR", Spanish},
+		{"ES", Spanish},
+		{"en;q=0.5, es;q=0.9", Spanish},
+		{"fr, es;q=0.1", Spanish},
+		{"fr-FR, de", English},
+		{"*", English},
+		{"pt;q=0, es", Spanish},
+		{"pt;q=abc", English},
+	}
+	for _, c := range cases {
+		if got := Negotiate(c.header); got != c.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", c.header, got, c.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(Portuguese, "Invalid zipcode"); got != "CEP inválido" {
+		t.Errorf("unexpected pt-BR title: %q", got)
+	}
+	if got := T(Spanish, "Zipcode not found"); got != "Código postal no encontrado" {
+		t.Errorf("unexpected es title: %q", got)
+	}
+	// A WeatherAPI varia capitalização e espaços nas descrições
+	if got := T(Portuguese, "Patchy rain possible "); got != "Possibilidade de chuva irregular" {
+		t.Errorf("expected normalized lookup, got %q", got)
+	}
+	// Mensagens sem tradução e o idioma de origem são devolvidos sem alteração
+	if got := T(Portuguese, "service-b responded with status 500"); got != "service-b responded with status 500" {
+		t.Errorf("expected untranslated message, got %q", got)
+	}
+	if got := T(English, "Invalid zipcode"); got != "Invalid zipcode" {
+		t.Errorf("expected source message, got %q", got)
+	}
+}
+
+// TestCatalogs garante que todos os idiomas traduzem as mesmas mensagens
+func TestCatalogs(t *testing.T) {
+	for _, lang := range Supported {
+		if lang == English {
+			continue
+		}
+		if len(catalogs[lang]) == 0 {
+			t.Fatalf("missing catalog for %s", lang)
+		}
+		for _, other := range Supported {
+			if other == English || other == lang {
+				continue
+			}
+			for msg := range catalogs[lang] {
+				if _, ok := catalogs[other][msg]; !ok {
+					t.Errorf("%q is translated to %s but not to %s", msg, lang, other)
+				}
+			}
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got Lang
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "es-MX,es;q=0.9")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != Spanish {
+		t.Fatalf("expected %s in context, got %s", Spanish, got)
+	}
+}
@@ -2,23 +2,37 @@
 package location
 
 import (
+	"cep-weather/internal/ratelimit"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	// Importação do OpenTelemetry para instrumentação HTTP
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 )
 
 // BaseURL é a URL base da API ViaCEP para consulta de CEP
 // Formato: https://viacep.com.br/ws/{CEP}/json/
 var BaseURL = "https://viacep.com.br/ws/%s/json/"
 
+// ErrNotFound indica que o CEP não existe na base da ViaCEP
+var ErrNotFound = errors.New("zipcode not found")
+
+// limiter limita a taxa de chamadas à ViaCEP (nil não limita)
+var limiter atomic.Pointer[ratelimit.Limiter]
+
+// SetLimiter define o limitador de taxa das chamadas à ViaCEP, que bloqueia
+// clientes acima do uso justo; nil remove o limite
+func SetLimiter(l *ratelimit.Limiter) {
+	limiter.Store(l)
+}
+
 // Location representa a estrutura de resposta da API ViaCEP
 type Location struct {
 	City string `json:"localidade"` // Nome da cidade encontrada
@@ -37,7 +51,7 @@ type Location struct {
 //
 // Retorna:
 //   - Location: Estrutura com o nome da cidade encontrada
-//   - error: Erro caso a consulta falhe ou CEP não seja encontrado
+//   - error: Erro caso a consulta falhe ou ErrNotFound se o CEP não for encontrado
 func GetLocationByCEP(ctx context.Context, cep string) (Location, error) {
 	// Obtém o tracer para criar spans de rastreamento
 	tracer := otel.Tracer("location-service")
@@ -56,6 +70,14 @@ func GetLocationByCEP(ctx context.Context, cep string) (Location, error) {
 	)
 	
 	url := fmt.Sprintf(BaseURL, cep)
+
+	// Aguarda um token do limitador de taxa (ver SetLimiter); sem token a
+	// tempo, a chamada falha com ratelimit.RateLimited
+	if err := limiter.Load().Wait(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return Location{}, err
+	}
 	
 	// Cria um cliente HTTP instrumentado com OpenTelemetry
 	// O transporte OTEL automaticamente cria spans adicionais para a requisição HTTP
@@ -98,7 +120,7 @@ func GetLocationByCEP(ctx context.Context, cep string) (Location, error) {
 
 	// Valida se a cidade foi encontrada (resposta vazia indica CEP não encontrado)
 	if loc.City == "" {
-		err := fmt.Errorf("zipcode not found")
+		err := ErrNotFound
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return Location{}, err
@@ -0,0 +1,198 @@
+// Pacote problem padroniza as respostas de erro dos serviços no formato
+// RFC 7807 (Problem Details for HTTP APIs), usando o content-type
+// application/problem+json.
+//
+// Cada erro possui um código estável (campo "code") que pode ser usado pelos
+// clientes para tratamento programático, além do trace_id do span atual para
+// facilitar a correlação com os traces no Zipkin.
+package problem
+
+import (
+	"cep-weather/internal/i18n"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContentType é o tipo de mídia definido pela RFC 7807 para respostas de erro
+const ContentType = "application/problem+json"
+
+// TypeBase é o prefixo usado para montar o campo "type" de cada problema
+// O campo "type" é uma URI que identifica a categoria do erro
+const TypeBase = "urn:cep-weather:problem:"
+
+// Legacy ativa o modo de compatibilidade com o formato antigo de erros
+// Quando verdadeiro, os erros são enviados como texto puro (http.Error) com as
+// mensagens originais ("invalid zipcode", "can not find zipcode", etc.),
+// mantendo o contrato esperado pelos testes de aceitação existentes
+var Legacy bool
+
+// Kind descreve uma categoria de erro exposta pela API
+type Kind struct {
+	Code          string // Código estável e legível por máquina (ex: "invalid_zipcode")
+	Status        int    // Código de status HTTP associado ao erro
+	Title         string // Resumo curto e legível por humanos
+	LegacyMessage string // Mensagem em texto puro usada no modo legado
+}
+
+// Categorias de erro conhecidas pelos serviços
+// Os códigos fazem parte do contrato público e não devem ser alterados
+var (
+	// InvalidZipcode indica que o CEP recebido não possui formato válido (422)
+	InvalidZipcode = Kind{
+		Code:          "invalid_zipcode",
+		Status:        http.StatusUnprocessableEntity,
+		Title:         "Invalid zipcode",
+		LegacyMessage: "invalid zipcode",
+	}
+	// InvalidBatch indica que o lote de CEPs está vazio ou excede o limite (422)
+	InvalidBatch = Kind{
+		Code:          "invalid_batch",
+		Status:        http.StatusUnprocessableEntity,
+		Title:         "Invalid batch",
+		LegacyMessage: "invalid batch",
+	}
+	// InvalidDays indica que a quantidade de dias da previsão está fora dos limites (422)
+	InvalidDays = Kind{
+		Code:          "invalid_days",
+		Status:        http.StatusUnprocessableEntity,
+		Title:         "Invalid forecast days",
+		LegacyMessage: "invalid days",
+	}
+	// InvalidDate indica que a data do histórico é inválida ou não está no passado (422)
+	InvalidDate = Kind{
+		Code:          "invalid_date",
+		Status:        http.StatusUnprocessableEntity,
+		Title:         "Invalid date",
+		LegacyMessage: "invalid date",
+	}
+	// ZipcodeNotFound indica que o CEP não foi encontrado na base da ViaCEP (404)
+	ZipcodeNotFound = Kind{
+		Code:          "zipcode_not_found",
+		Status:        http.StatusNotFound,
+		Title:         "Zipcode not found",
+		LegacyMessage: "can not find zipcode",
+	}
+	// MethodNotAllowed indica que o método HTTP não é suportado pelo endpoint (405)
+	MethodNotAllowed = Kind{
+		Code:          "method_not_allowed",
+		Status:        http.StatusMethodNotAllowed,
+		Title:         "Method not allowed",
+		LegacyMessage: "Method not allowed",
+	}
+	// NotAcceptable indica que nenhum dos formatos do cabeçalho Accept é suportado (406)
+	NotAcceptable = Kind{
+		Code:          "not_acceptable",
+		Status:        http.StatusNotAcceptable,
+		Title:         "Not acceptable",
+		LegacyMessage: "Not acceptable",
+	}
+	// Internal indica uma falha inesperada no processamento da requisição (500)
+	Internal = Kind{
+		Code:          "internal_error",
+		Status:        http.StatusInternalServerError,
+		Title:         "Internal server error",
+		LegacyMessage: "Internal server error",
+	}
+	// BadGateway indica que um serviço dependente respondeu com falha (502)
+	BadGateway = Kind{
+		Code:          "bad_gateway",
+		Status:        http.StatusBadGateway,
+		Title:         "Bad gateway",
+		LegacyMessage: "Bad gateway",
+	}
+	// UpstreamUnavailable indica que um serviço dependente está inacessível (503)
+	UpstreamUnavailable = Kind{
+		Code:          "upstream_unavailable",
+		Status:        http.StatusServiceUnavailable,
+		Title:         "Upstream service unavailable",
+		LegacyMessage: "Service unavailable",
+	}
+	// Unauthorized indica que a chave de API está ausente ou não é reconhecida (401)
+	Unauthorized = Kind{
+		Code:          "unauthorized",
+		Status:        http.StatusUnauthorized,
+		Title:         "Unauthorized",
+		LegacyMessage: "unauthorized",
+	}
+	// Forbidden indica que o cliente não tem acesso ao endpoint (403)
+	Forbidden = Kind{
+		Code:          "forbidden",
+		Status:        http.StatusForbidden,
+		Title:         "Forbidden",
+		LegacyMessage: "forbidden",
+	}
+	// RateLimited indica que o cliente excedeu a sua cota de requisições (429)
+	RateLimited = Kind{
+		Code:          "rate_limited",
+		Status:        http.StatusTooManyRequests,
+		Title:         "Too many requests",
+		LegacyMessage: "too many requests",
+	}
+	// UpstreamTimeout indica que um serviço dependente não respondeu a tempo (504)
+	UpstreamTimeout = Kind{
+		Code:          "upstream_timeout",
+		Status:        http.StatusGatewayTimeout,
+		Title:         "Upstream service timeout",
+		LegacyMessage: "Gateway timeout",
+	}
+)
+
+// Problem representa o corpo de uma resposta de erro no formato RFC 7807
+type Problem struct {
+	Type     string `json:"type"`               // URI que identifica a categoria do erro
+	Title    string `json:"title"`              // Resumo curto do erro
+	Status   int    `json:"status"`             // Código de status HTTP
+	Detail   string `json:"detail,omitempty"`   // Explicação específica desta ocorrência
+	Instance string `json:"instance,omitempty"` // Caminho da requisição que originou o erro
+	Code     string `json:"code"`               // Código estável do erro
+	TraceID  string `json:"trace_id,omitempty"` // ID do trace para correlação no Zipkin
+}
+
+// New cria um Problem para a categoria informada
+// O trace_id é obtido do span presente no contexto, quando existir
+// Título e detalhe são traduzidos para o idioma do contexto (ver i18n.WithLang);
+// type e code não são traduzidos, pois fazem parte do contrato
+func New(ctx context.Context, kind Kind, detail string) Problem {
+	lang := i18n.FromContext(ctx)
+	p := Problem{
+		Type:   TypeBase + kind.Code,
+		Title:  i18n.T(lang, kind.Title),
+		Status: kind.Status,
+		Detail: i18n.T(lang, detail),
+		Code:   kind.Code,
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		p.TraceID = sc.TraceID().String()
+	}
+	return p
+}
+
+// Write envia a resposta de erro ao cliente
+//
+// No modo padrão, o corpo é um JSON no formato RFC 7807 com content-type
+// application/problem+json. No modo legado (Legacy = true), o corpo é a
+// mensagem em texto puro original, preservando o contrato anterior.
+//
+// O idioma do título e do detalhe é negociado pelo cabeçalho Accept-Language
+// (ver i18n.FromRequest) e informado em Content-Language. O modo legado não
+// é traduzido.
+func Write(w http.ResponseWriter, r *http.Request, kind Kind, detail string) {
+	if Legacy {
+		http.Error(w, kind.LegacyMessage, kind.Status)
+		return
+	}
+
+	lang := i18n.FromRequest(r)
+	p := New(i18n.WithLang(r.Context(), lang), kind, detail)
+	p.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(kind.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,101 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestWrite_ProblemJSON(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	req := httptest.NewRequest(http.MethodPost, "/weather", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	Write(rec, req, InvalidZipcode, "cep must contain exactly 8 digits")
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("expected content-type %s, got %s", ContentType, ct)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("expected valid JSON body, got %v", err)
+	}
+	if p.Code != "invalid_zipcode" || p.Type != TypeBase+"invalid_zipcode" {
+		t.Fatalf("unexpected code/type: %+v", p)
+	}
+	if p.Status != http.StatusUnprocessableEntity || p.Instance != "/weather" {
+		t.Fatalf("unexpected status/instance: %+v", p)
+	}
+	if p.TraceID != span.SpanContext().TraceID().String() {
+		t.Fatalf("expected trace_id %s, got %s", span.SpanContext().TraceID(), p.TraceID)
+	}
+}
+
+func TestWrite_Legacy(t *testing.T) {
+	Legacy = true
+	defer func() { Legacy = false }()
+
+	cases := []struct {
+		kind Kind
+		body string
+	}{
+		{InvalidZipcode, "invalid zipcode"},
+		{ZipcodeNotFound, "can not find zipcode"},
+		{Internal, "Internal server error"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/weather", nil)
+		rec := httptest.NewRecorder()
+
+		Write(rec, req, c.kind, "ignored in legacy mode")
+
+		if rec.Code != c.kind.Status {
+			t.Errorf("%s: expected status %d, got %d", c.kind.Code, c.kind.Status, rec.Code)
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != c.body {
+			t.Errorf("%s: expected body %q, got %q", c.kind.Code, c.body, got)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("%s: expected text/plain, got %s", c.kind.Code, ct)
+		}
+	}
+}
+
+func TestWrite_Localized(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/weather", nil)
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
+	rec := httptest.NewRecorder()
+
+	Write(rec, req, InvalidZipcode, "cep must contain 8 digits, optionally formatted as 00000-000")
+
+	if cl := rec.Header().Get("Content-Language"); cl != "pt-BR" {
+		t.Fatalf("expected Content-Language pt-BR, got %q", cl)
+	}
+	if v := rec.Header().Get("Vary"); v != "Accept-Language" {
+		t.Fatalf("expected Vary: Accept-Language, got %q", v)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("expected valid JSON body, got %v", err)
+	}
+	if p.Title != "CEP inválido" || p.Detail != "o CEP deve conter 8 dígitos, opcionalmente no formato 00000-000" {
+		t.Fatalf("expected localized title/detail, got %+v", p)
+	}
+	// Os códigos legíveis por máquina não são traduzidos
+	if p.Code != "invalid_zipcode" || p.Type != TypeBase+"invalid_zipcode" {
+		t.Fatalf("unexpected code/type: %+v", p)
+	}
+}
@@ -0,0 +1,165 @@
+// Pacote ratelimit fornece os limites de taxa dos serviços
+//
+// Chamadas às APIs externas (Limiter): a ViaCEP bloqueia clientes que excedem
+// o uso justo e a WeatherAPI cobra por chamada, então cada cliente do Serviço
+// B passa por um token bucket antes de chamar a sua API. Quando não há token
+// disponível, a chamada aguarda na fila (Queue) até o prazo da requisição ou
+// falha imediatamente (FailFast), sempre com o erro tipado RateLimited.
+//
+// Requisições recebidas (Quota e Store): o Serviço A conta as requisições de
+// cada cliente em janelas fixas, conforme a cota do seu plano (tier).
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Mode define o comportamento do Limiter quando não há token disponível
+type Mode string
+
+const (
+	Queue    Mode = "queue"     // Aguarda o próximo token, desde que dentro do prazo da requisição
+	FailFast Mode = "fail-fast" // Falha imediatamente com RateLimited
+)
+
+// RateLimited é o erro devolvido quando a chamada não obtém um token a tempo
+type RateLimited struct {
+	Limiter    string        // Nome do limitador (ex: "viacep")
+	RetryAfter time.Duration // Tempo até o próximo token disponível
+}
+
+func (e *RateLimited) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded; retry after %s", e.Limiter, e.RetryAfter.Round(time.Millisecond))
+}
+
+// Limiter é um token bucket: acumula até burst tokens, repostos à taxa de
+// rate tokens por segundo, e cada chamada consome um token
+//
+// Um Limiter nil ou com rate <= 0 não limita as chamadas.
+// O Limiter é seguro para uso concorrente.
+type Limiter struct {
+	name  string
+	rate  float64 // Tokens repostos por segundo
+	burst float64 // Capacidade máxima do balde
+	mode  Mode
+
+	mu     sync.Mutex
+	tokens float64          // Tokens disponíveis (negativo quando há chamadas na fila)
+	last   time.Time        // Última reposição
+	now    func() time.Time // Relógio, substituível em testes
+}
+
+// New cria o limitador de uma API externa
+//
+// Parâmetros:
+//   - name: Nome da API, usado nos spans e nos erros (ex: "viacep", "weatherapi")
+//   - rate: Chamadas por segundo (0 desativa o limite)
+//   - burst: Chamadas permitidas de uma vez, antes de aplicar a taxa (mínimo 1)
+//   - mode: Comportamento quando não há token disponível (Queue ou FailFast)
+func New(name string, rate float64, burst int, mode Mode) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		name:   name,
+		rate:   rate,
+		burst:  float64(burst),
+		mode:   mode,
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// reserve consome um token, que pode estar disponível agora ou no futuro
+//
+// Retorna o tempo de espera até o token (0 quando imediato) e false quando a
+// espera excederia max; nesse caso, nenhum token é consumido
+func (l *Limiter) reserve(max time.Duration) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if wait > max {
+		return wait, false
+	}
+	l.tokens--
+	return wait, true
+}
+
+// cancel devolve um token reservado e não utilizado
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// Wait obtém um token para uma chamada à API
+//
+// No modo Queue, aguarda o próximo token desde que ele fique disponível antes
+// do prazo de ctx; no modo FailFast, não aguarda. Esperas e recusas geram o
+// span "ratelimit-wait", filho do span da chamada.
+//
+// Retorna *RateLimited quando não há token a tempo, ou o erro de ctx se ele
+// for cancelado durante a espera
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+
+	max := time.Duration(0)
+	if l.mode == Queue {
+		max = time.Duration(math.MaxInt64)
+		if deadline, ok := ctx.Deadline(); ok {
+			max = deadline.Sub(l.now())
+		}
+	}
+	wait, ok := l.reserve(max)
+	if ok && wait == 0 {
+		return nil
+	}
+
+	_, span := otel.Tracer("ratelimit").Start(ctx, "ratelimit-wait", trace.WithAttributes(
+		attribute.String("ratelimit.limiter", l.name),
+		attribute.String("ratelimit.mode", string(l.mode)),
+		attribute.Int64("ratelimit.wait_ms", wait.Milliseconds()),
+	))
+	defer span.End()
+
+	if !ok {
+		err := &RateLimited{Limiter: l.name, RetryAfter: wait}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		span.RecordError(ctx.Err())
+		span.SetStatus(codes.Error, ctx.Err().Error())
+		return ctx.Err()
+	}
+}
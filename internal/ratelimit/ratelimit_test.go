@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Burst(t *testing.T) {
+	now := time.Now()
+	l := New("test", 1, 2, FailFast)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("expected burst of 2, call %d failed with %v", i, err)
+		}
+	}
+
+	err := l.Wait(context.Background())
+	var limited *RateLimited
+	if !errors.As(err, &limited) || limited.Limiter != "test" || limited.RetryAfter != time.Second {
+		t.Fatalf("expected RateLimited with 1s retry, got %v", err)
+	}
+
+	// Os tokens são repostos à taxa configurada
+	now = now.Add(time.Second)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected token after 1s, got %v", err)
+	}
+}
+
+func TestLimiter_Queue(t *testing.T) {
+	l := New("test", 50, 1, Queue)
+	l.Wait(context.Background())
+
+	// O próximo token fica disponível em 20ms: a chamada aguarda na fila
+	start := time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("expected queued call to succeed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected call to wait for a token, waited %s", elapsed)
+	}
+}
+
+func TestLimiter_QueueDeadline(t *testing.T) {
+	l := New("test", 1, 1, Queue)
+	l.Wait(context.Background())
+
+	// O próximo token só fica disponível após o prazo: falha sem aguardar
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var limited *RateLimited
+	if err := l.Wait(ctx); !errors.As(err, &limited) {
+		t.Fatalf("expected RateLimited, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("expected call to fail before the deadline")
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	var nilLimiter *Limiter
+	for _, l := range []*Limiter{nilLimiter, New("test", 0, 1, FailFast)} {
+		for i := 0; i < 100; i++ {
+			if err := l.Wait(context.Background()); err != nil {
+				t.Fatalf("expected no limit, got %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Quota é a cota de requisições de um plano (tier) de clientes:
+// no máximo Limit requisições a cada Window
+type Quota struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Store guarda os contadores das cotas de requisições por cliente
+//
+// A implementação padrão (MemoryStore) mantém os contadores na memória do
+// processo, o que basta para uma única instância. Para que várias instâncias
+// compartilhem as cotas, basta implementar Store sobre um armazenamento
+// compartilhado (ex: INCR e EXPIRE do Redis).
+type Store interface {
+	// Incr soma n requisições ao contador do cliente na janela atual
+	// (n = 0 apenas consulta o contador e n negativo devolve unidades, ex:
+	// INCRBY do Redis)
+	//
+	// Retorna a quantidade de requisições na janela (incluindo estas) e o
+	// instante em que a janela termina e o contador é zerado
+	Incr(ctx context.Context, key string, n int, window time.Duration) (count int, reset time.Time, err error)
+}
+
+// counter é o contador de requisições de um cliente em uma janela
+type counter struct {
+	count int
+	reset time.Time
+}
+
+// MemoryStore é o Store em memória, seguro para uso concorrente
+// Cada cliente tem uma janela fixa, iniciada na sua primeira requisição
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+	now       func() time.Time // Relógio, substituível em testes
+}
+
+// sweepInterval é o intervalo mínimo entre as remoções de janelas encerradas
+const sweepInterval = time.Minute
+
+// NewMemoryStore cria um Store em memória
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: make(map[string]*counter), now: time.Now}
+}
+
+// Incr implementa Store
+func (s *MemoryStore) Incr(_ context.Context, key string, n int, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.reset) {
+		c = &counter{reset: now.Add(window)}
+		s.counters[key] = c
+	}
+	c.count += n
+	return c.count, c.reset, nil
+}
+
+// sweep remove as janelas encerradas, evitando que clientes que não voltam
+// ocupem memória indefinidamente; deve ser chamado com s.mu travado
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, c := range s.counters {
+		if !now.Before(c.reset) {
+			delete(s.counters, key)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for want := 1; want <= 3; want++ {
+		count, reset, _ := s.Incr(ctx, "a", 1, time.Minute)
+		if count != want || !reset.Equal(now.Add(time.Minute)) {
+			t.Fatalf("expected count %d resetting in 1m, got %d at %s", want, count, reset)
+		}
+	}
+	if count, _, _ := s.Incr(ctx, "b", 1, time.Minute); count != 1 {
+		t.Fatalf("expected independent counter per key, got %d", count)
+	}
+
+	// n soma várias requisições de uma vez; n = 0 apenas consulta
+	if count, _, _ := s.Incr(ctx, "b", 4, time.Minute); count != 5 {
+		t.Fatalf("expected count 5 after adding 4, got %d", count)
+	}
+	if count, _, _ := s.Incr(ctx, "b", 0, time.Minute); count != 5 {
+		t.Fatalf("expected n = 0 to leave the counter unchanged, got %d", count)
+	}
+
+	// Ao fim da janela, o contador é zerado e as janelas encerradas são removidas
+	now = now.Add(2 * time.Minute)
+	if count, _, _ := s.Incr(ctx, "a", 1, time.Minute); count != 1 {
+		t.Fatalf("expected counter to reset after the window, got %d", count)
+	}
+	if _, ok := s.counters["b"]; ok {
+		t.Fatalf("expected expired window to be swept")
+	}
+}
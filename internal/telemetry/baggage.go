@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// baggageProcessor copia membros do baggage para os atributos dos spans
+//
+// O baggage recebido do chamador (ex: client.name, definido pelo Serviço A)
+// fica no contexto, mas não nos spans; com o processor, cada span iniciado
+// no serviço recebe os membros selecionados como atributos, com o mesmo nome,
+// permitindo filtrar os traces por cliente no Zipkin.
+type baggageProcessor struct {
+	keys []string
+}
+
+// NewBaggageSpanProcessor cria o processor que copia os membros informados
+// Membros ausentes no baggage são ignorados
+//
+// Parâmetros:
+//   - keys: Membros do baggage copiados (ex: "client.name", "request.id")
+func NewBaggageSpanProcessor(keys ...string) sdktrace.SpanProcessor {
+	return baggageProcessor{keys: keys}
+}
+
+// OnStart copia os membros do baggage do contexto em que o span foi iniciado
+func (p baggageProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	b := baggage.FromContext(ctx)
+	for _, key := range p.keys {
+		if m := b.Member(key); m.Key() != "" {
+			s.SetAttributes(attribute.String(key, m.Value()))
+		}
+	}
+}
+
+func (baggageProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (baggageProcessor) Shutdown(context.Context) error   { return nil }
+func (baggageProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,36 @@
+package telemetry
+
+import (
+	"maps"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBaggageSpanProcessor(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewBaggageSpanProcessor("client.name", "request.id", "tenant.id")),
+		sdktrace.WithSpanProcessor(sr),
+	)
+	ctx := remoteContext(t, "client.name=acme,request.id=abc-123,other=ignored")
+
+	ctx, parent := tp.Tracer("test").Start(ctx, "process-weather-request")
+	_, child := tp.Tracer("test").Start(ctx, "viacep-lookup")
+	child.End()
+	parent.End()
+
+	// Os membros selecionados chegam a todos os spans; os demais e os ausentes, não
+	want := map[attribute.Key]string{"client.name": "acme", "request.id": "abc-123"}
+	for _, s := range sr.Ended() {
+		got := map[attribute.Key]string{}
+		for _, kv := range s.Attributes() {
+			got[kv.Key] = kv.Value.AsString()
+		}
+		if !maps.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", s.Name(), want, got)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// InitMeter inicializa o provedor de métricas do OpenTelemetry
+//
+// As métricas (ex: weatherapi.key.calls e as métricas HTTP do otelhttp) são
+// expostas no formato do Prometheus pelo handler retornado, a ser servido em
+// GET /metrics. Cada chamada usa um registro próprio, sem depender do
+// registro global do Prometheus.
+//
+// Parâmetros:
+//   - serviceName: Nome do serviço (ex: "service-a", "service-b")
+//
+// Retorna:
+//   - *sdkmetric.MeterProvider: Provedor de métricas, que deve ser encerrado com Shutdown
+//   - http.Handler: Handler que expõe as métricas no formato do Prometheus
+//   - error: Erro caso a configuração falhe
+func InitMeter(serviceName string) (*sdkmetric.MeterProvider, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	// Instrumentos criados antes desta chamada (ex: variáveis de pacote) passam
+	// a usar o provedor configurado
+	otel.SetMeterProvider(mp)
+
+	return mp, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestInitMeter(t *testing.T) {
+	mp, handler, err := InitMeter("test-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mp.Shutdown(context.Background()) })
+
+	counter, err := otel.Meter("test").Int64Counter("test.calls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(context.Background(), 3)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "test_calls_total") || !strings.Contains(body, `service_name="test-service"`) {
+		t.Fatalf("expected counter in the Prometheus output, got:\n%s", body)
+	}
+}
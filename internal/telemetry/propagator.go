@@ -0,0 +1,69 @@
+package telemetry
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// defaultPropagators são os propagadores usados quando SetPropagators não é
+// chamada: o trace (cabeçalho traceparent, W3C Trace Context) e o baggage
+// (cabeçalho baggage, W3C Baggage), que leva ao Serviço B a identidade do
+// cliente autenticado no Serviço A
+var defaultPropagators = []string{"tracecontext", "baggage"}
+
+// available reúne os propagadores aceitos, com os nomes de OTEL_PROPAGATORS
+// O B3 atende chamadores instrumentados com o Zipkin; na extração, os dois
+// formatos (cabeçalho único b3 ou X-B3-*) são aceitos
+var available = map[string]propagation.TextMapPropagator{
+	"tracecontext": propagation.TraceContext{},
+	"baggage":      propagation.Baggage{},
+	"b3":           b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)),
+	"b3multi":      b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)),
+}
+
+// propagator e tracePropagator são definidos na inicialização (ver SetPropagators)
+var propagator, tracePropagator = compose(defaultPropagators)
+
+// SetPropagators define os propagadores do contexto entre os serviços e os
+// instala como globais (usados pelo otelhttp)
+// Deve ser chamada na inicialização, antes de criar clientes e servidores
+//
+// Parâmetros:
+//   - names: Propagadores, na ordem de extração: "tracecontext", "baggage",
+//     "b3" (cabeçalho único) e "b3multi" (cabeçalhos X-B3-*)
+func SetPropagators(names ...string) error {
+	for _, name := range names {
+		if _, ok := available[name]; !ok {
+			return fmt.Errorf("unknown propagator %q", name)
+		}
+	}
+	propagator, tracePropagator = compose(names)
+	otel.SetTextMapPropagator(propagator)
+	return nil
+}
+
+// compose monta o propagador com todos os formatos e o apenas com os de trace
+func compose(names []string) (all, trace propagation.TextMapPropagator) {
+	var ps, tps []propagation.TextMapPropagator
+	for _, name := range names {
+		ps = append(ps, available[name])
+		if name != "baggage" {
+			tps = append(tps, available[name])
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(ps...), propagation.NewCompositeTextMapPropagator(tps...)
+}
+
+// Propagator retorna o propagador do contexto entre os serviços (trace e baggage)
+func Propagator() propagation.TextMapPropagator {
+	return propagator
+}
+
+// TracePropagator retorna o propagador apenas do trace, sem o baggage
+// Usado na borda do sistema, onde o baggage enviado pelo cliente não é confiável
+func TracePropagator() propagation.TextMapPropagator {
+	return tracePropagator
+}
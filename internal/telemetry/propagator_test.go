@@ -0,0 +1,69 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// remoteContext retorna um contexto com um span remoto e o baggage informado
+func remoteContext(t *testing.T, members string) context.Context {
+	t.Helper()
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	b, err := baggage.Parse(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return baggage.ContextWithBaggage(trace.ContextWithRemoteSpanContext(context.Background(), sc), b)
+}
+
+func TestSetPropagators(t *testing.T) {
+	t.Cleanup(func() { SetPropagators(defaultPropagators...) })
+	ctx := remoteContext(t, "client.name=acme")
+
+	// Padrão: traceparent e baggage
+	h := http.Header{}
+	Propagator().Inject(ctx, propagation.HeaderCarrier(h))
+	if h.Get("traceparent") == "" || h.Get("baggage") != "client.name=acme" || h.Get("b3") != "" {
+		t.Fatalf("unexpected default headers: %v", h)
+	}
+
+	// Com B3, o contexto de chamadores Zipkin é extraído nos dois formatos
+	if err := SetPropagators("tracecontext", "baggage", "b3"); err != nil {
+		t.Fatal(err)
+	}
+	h = http.Header{}
+	Propagator().Inject(ctx, propagation.HeaderCarrier(h))
+	if h.Get("b3") == "" || h.Get("X-B3-TraceId") != "" {
+		t.Fatalf("expected single b3 header, got %v", h)
+	}
+	multi := http.Header{
+		"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+		"X-B3-Spanid":  {"00f067aa0ba902b7"},
+		"X-B3-Sampled": {"1"},
+	}
+	sc := trace.SpanContextFromContext(Propagator().Extract(context.Background(), propagation.HeaderCarrier(multi)))
+	if sc.TraceID() != trace.SpanContextFromContext(ctx).TraceID() || !sc.IsSampled() {
+		t.Fatalf("expected trace from X-B3-* headers, got %v", sc)
+	}
+
+	// Na borda, apenas o trace é extraído
+	h.Set("baggage", "client.name=intruder")
+	extracted := TracePropagator().Extract(context.Background(), propagation.HeaderCarrier(h))
+	if !trace.SpanContextFromContext(extracted).IsValid() || baggage.FromContext(extracted).Len() != 0 {
+		t.Fatalf("expected trace without baggage at the edge")
+	}
+
+	if err := SetPropagators("tracecontext", "jaeger"); err == nil {
+		t.Fatalf("expected unknown propagator to be rejected")
+	}
+}
@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ratioSampler amostra uma proporção dos traces, ajustável em tempo de execução
+//
+// A decisão é delegada a um ParentBased(TraceIDRatioBased): spans com pai
+// remoto seguem a decisão do chamador, de modo que um trace iniciado no
+// Serviço A nunca fica pela metade no Serviço B. Trocar a proporção afeta
+// apenas os spans criados depois da troca.
+type ratioSampler struct {
+	current atomic.Pointer[sdktrace.Sampler]
+}
+
+// ShouldSample delega a decisão ao amostrador configurado no momento
+func (s *ratioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(p)
+}
+
+// Description descreve o amostrador configurado no momento
+func (s *ratioSampler) Description() string {
+	return fmt.Sprintf("Adjustable{%s}", (*s.current.Load()).Description())
+}
+
+// sampler é o amostrador usado pelo provedor criado em InitTracer
+// Por padrão, todos os traces são amostrados
+var sampler = newRatioSampler(1)
+
+// newRatioSampler cria o amostrador com a proporção inicial informada
+func newRatioSampler(ratio float64) *ratioSampler {
+	s := &ratioSampler{}
+	s.set(ratio)
+	return s
+}
+
+// set substitui o amostrador atual de forma atômica
+func (s *ratioSampler) set(ratio float64) {
+	next := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	s.current.Store(&next)
+}
+
+// SetSampleRatio altera a proporção de traces amostrados (0 a 1)
+// Pode ser chamada a qualquer momento, inclusive com requisições em andamento
+//
+// Parâmetros:
+//   - ratio: Proporção dos traces iniciados neste serviço que serão amostrados
+//     (1 amostra todos, 0 desativa; valores fora do intervalo são limitados)
+func SetSampleRatio(ratio float64) {
+	sampler.set(ratio)
+}
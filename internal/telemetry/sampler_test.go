@@ -0,0 +1,22 @@
+package telemetry
+
+import (
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRatioSampler(t *testing.T) {
+	s := newRatioSampler(1)
+	params := sdktrace.SamplingParameters{TraceID: trace.TraceID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, Name: "span"}
+
+	if got := s.ShouldSample(params).Decision; got != sdktrace.RecordAndSample {
+		t.Fatalf("expected ratio 1 to sample, got %v", got)
+	}
+
+	s.set(0)
+	if got := s.ShouldSample(params).Decision; got != sdktrace.Drop {
+		t.Fatalf("expected ratio 0 to drop, got %v", got)
+	}
+}
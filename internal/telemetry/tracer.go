@@ -4,8 +4,6 @@ package telemetry
 
 // Importação dos pacotes necessários do OpenTelemetry
 import (
-	"os"
-	
 	"go.opentelemetry.io/otel"                         // Pacote principal do OpenTelemetry (tracer global)
 	"go.opentelemetry.io/otel/exporters/zipkin"        // Exportador para enviar traces ao Zipkin
 	"go.opentelemetry.io/otel/sdk/resource"            // Recursos do SDK (metadados do serviço)
@@ -17,26 +15,18 @@ import (
 // 
 // Esta função configura todo o sistema de rastreamento distribuído:
 // - Conecta ao Zipkin para visualização de traces
-// - Configura amostragem (sempre amostra todos os traces)
+// - Configura amostragem (proporção ajustável, ver SetSampleRatio)
 // - Define metadados do serviço para identificação
 //
 // Parâmetros:
 //   - serviceName: Nome do serviço (ex: "service-a", "service-b")
 //     Este nome aparecerá no Zipkin para identificar os traces
+//   - zipkinURL: Endpoint do Zipkin que receberá os spans (ver config.DefaultZipkinURL)
 //
 // Retorna:
 //   - *sdktrace.TracerProvider: Provedor de rastreamento configurado
 //   - error: Erro caso a configuração falhe
-func InitTracer(serviceName string) (*sdktrace.TracerProvider, error) {
-	// Obtém a URL do Zipkin das variáveis de ambiente
-	// Permite configurar dinamicamente a URL do Zipkin (útil para diferentes ambientes)
-	zipkinURL := os.Getenv("ZIPKIN_URL")
-	if zipkinURL == "" {
-		// URL padrão para ambiente Docker
-		// No docker-compose, o serviço Zipkin está disponível em "zipkin:9411"
-		zipkinURL = "http://zipkin:9411/api/v2/spans"
-	}
-	
+func InitTracer(serviceName, zipkinURL string) (*sdktrace.TracerProvider, error) {
 	// Cria um exportador Zipkin que enviará os traces para a URL especificada
 	// O exportador é responsável por serializar e enviar os spans ao Zipkin
 	exporter, err := zipkin.New(zipkinURL)
@@ -56,12 +46,16 @@ func InitTracer(serviceName string) (*sdktrace.TracerProvider, error) {
 	tp := sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter),                // Configura o exportador (envia spans em lotes para eficiência)
 		sdktrace.WithResource(resource),               // Adiciona os recursos (metadados do serviço)
-		sdktrace.WithSampler(sdktrace.AlwaysSample()), // Amostra todos os traces (100% das requisições são rastreadas)
+		sdktrace.WithSampler(sampler),                 // Amostra a proporção configurada em SetSampleRatio (padrão: todos os traces)
 	)
 
 	// Define o provedor de rastreamento como global para toda a aplicação
 	// Isso permite que qualquer parte do código use otel.Tracer() para criar spans
 	otel.SetTracerProvider(tp)
 
+	// Propaga o contexto nas chamadas HTTP instrumentadas com otelhttp
+	// (padrão: trace e baggage; ver SetPropagators)
+	otel.SetTextMapPropagator(Propagator())
+
 	return tp, nil
 }
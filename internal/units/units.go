@@ -0,0 +1,121 @@
+// Pacote units define grandezas físicas tipadas (temperatura, velocidade e
+// pressão) com conversões exatas entre unidades e arredondamento configurável.
+//
+// Cada grandeza guarda o valor em uma unidade base (Celsius, m/s, Pa) e
+// converte sob demanda, de modo que ida e volta entre unidades não acumula
+// erro além do arredondamento de ponto flutuante.
+package units
+
+import "math"
+
+// Constantes de conversão
+const (
+	// KelvinOffset é a diferença exata entre Kelvin e Celsius
+	KelvinOffset = 273.15
+	// LegacyKelvinOffset é a diferença usada pelo contrato original (K = C + 273)
+	LegacyKelvinOffset = 273.0
+
+	metersPerSecondPerKph = 1 / 3.6  // 1 km/h = 1/3.6 m/s
+	metersPerSecondPerMph = 0.44704  // 1 mph = 0,44704 m/s (exato, milha internacional)
+	pascalsPerHectopascal = 100.0    // 1 hPa = 1 mbar = 100 Pa
+	pascalsPerInchOfHg    = 3386.389 // 1 inHg a 0 °C (NIST)
+)
+
+// LegacyKelvin ativa o modo de compatibilidade com o contrato original, em que
+// Kelvin é calculado como C + 273 (em vez de C + 273,15)
+var LegacyKelvin bool
+
+// Precision é a quantidade de casas decimais usada por Round
+// Um valor negativo desativa o arredondamento
+var Precision = 2
+
+// kelvinOffset retorna a diferença entre Kelvin e Celsius conforme o modo ativo
+func kelvinOffset() float64 {
+	if LegacyKelvin {
+		return LegacyKelvinOffset
+	}
+	return KelvinOffset
+}
+
+// Round arredonda o valor para Precision casas decimais
+// Evita que conversões exponham ruído de ponto flutuante (ex: 71.60000000000001)
+func Round(v float64) float64 {
+	return RoundTo(v, Precision)
+}
+
+// RoundTo arredonda o valor para a quantidade de casas decimais informada
+// Metades são arredondadas para longe do zero; precision negativo devolve o valor original
+func RoundTo(v float64, precision int) float64 {
+	if precision < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	p := math.Pow10(precision)
+	r := math.Round(v*p) / p
+	if math.IsInf(r, 0) {
+		return v // Valores muito grandes já não têm casas decimais a arredondar
+	}
+	return r
+}
+
+// Temperature é uma temperatura, armazenada em graus Celsius
+type Temperature struct{ c float64 }
+
+// Celsius cria uma temperatura a partir de graus Celsius
+func Celsius(v float64) Temperature { return Temperature{c: v} }
+
+// Fahrenheit cria uma temperatura a partir de graus Fahrenheit
+func Fahrenheit(v float64) Temperature { return Temperature{c: (v - 32) / 1.8} }
+
+// Kelvin cria uma temperatura a partir de Kelvin
+func Kelvin(v float64) Temperature { return Temperature{c: v - kelvinOffset()} }
+
+// Celsius retorna a temperatura em graus Celsius
+func (t Temperature) Celsius() float64 { return t.c }
+
+// Fahrenheit retorna a temperatura em graus Fahrenheit (F = C * 1.8 + 32)
+func (t Temperature) Fahrenheit() float64 { return t.c*1.8 + 32 }
+
+// Kelvin retorna a temperatura em Kelvin (K = C + 273.15, ou C + 273 no modo legado)
+func (t Temperature) Kelvin() float64 { return t.c + kelvinOffset() }
+
+// Speed é uma velocidade, armazenada em metros por segundo
+type Speed struct{ ms float64 }
+
+// MetersPerSecond cria uma velocidade a partir de m/s
+func MetersPerSecond(v float64) Speed { return Speed{ms: v} }
+
+// KilometersPerHour cria uma velocidade a partir de km/h
+func KilometersPerHour(v float64) Speed { return Speed{ms: v * metersPerSecondPerKph} }
+
+// MilesPerHour cria uma velocidade a partir de milhas por hora
+func MilesPerHour(v float64) Speed { return Speed{ms: v * metersPerSecondPerMph} }
+
+// MetersPerSecond retorna a velocidade em m/s
+func (s Speed) MetersPerSecond() float64 { return s.ms }
+
+// KilometersPerHour retorna a velocidade em km/h
+func (s Speed) KilometersPerHour() float64 { return s.ms * 3.6 }
+
+// MilesPerHour retorna a velocidade em milhas por hora
+func (s Speed) MilesPerHour() float64 { return s.ms / metersPerSecondPerMph }
+
+// Pressure é uma pressão atmosférica, armazenada em pascals
+type Pressure struct{ pa float64 }
+
+// Pascals cria uma pressão a partir de pascals
+func Pascals(v float64) Pressure { return Pressure{pa: v} }
+
+// Hectopascals cria uma pressão a partir de hectopascals (equivalente a milibares)
+func Hectopascals(v float64) Pressure { return Pressure{pa: v * pascalsPerHectopascal} }
+
+// InchesOfMercury cria uma pressão a partir de polegadas de mercúrio
+func InchesOfMercury(v float64) Pressure { return Pressure{pa: v * pascalsPerInchOfHg} }
+
+// Pascals retorna a pressão em pascals
+func (p Pressure) Pascals() float64 { return p.pa }
+
+// Hectopascals retorna a pressão em hectopascals (milibares)
+func (p Pressure) Hectopascals() float64 { return p.pa / pascalsPerHectopascal }
+
+// InchesOfMercury retorna a pressão em polegadas de mercúrio
+func (p Pressure) InchesOfMercury() float64 { return p.pa / pascalsPerInchOfHg }
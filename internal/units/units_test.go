@@ -0,0 +1,112 @@
+package units
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+// approx compara dois valores com tolerância relativa, absorvendo o erro de ponto flutuante
+func approx(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// bounded limita os valores gerados pelo testing/quick a uma faixa física plausível
+func bounded(v float64) float64 {
+	return math.Mod(v, 1e6)
+}
+
+func TestTemperature_Conversions(t *testing.T) {
+	c := Celsius(20)
+	if c.Fahrenheit() != 68 || c.Kelvin() != 293.15 {
+		t.Fatalf("unexpected conversions: %v °F, %v K", c.Fahrenheit(), c.Kelvin())
+	}
+	if Fahrenheit(212).Celsius() != 100 || Kelvin(0).Celsius() != -273.15 {
+		t.Fatalf("unexpected conversions to Celsius")
+	}
+}
+
+func TestTemperature_LegacyKelvin(t *testing.T) {
+	LegacyKelvin = true
+	defer func() { LegacyKelvin = false }()
+
+	if k := Celsius(20).Kelvin(); k != 293 {
+		t.Fatalf("expected legacy 293 K, got %v", k)
+	}
+	if c := Kelvin(293).Celsius(); c != 20 {
+		t.Fatalf("expected 20 °C, got %v", c)
+	}
+}
+
+func TestTemperature_RoundTrip(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		LegacyKelvin = legacy
+		roundTrip := func(v float64) bool {
+			v = bounded(v)
+			return approx(Fahrenheit(Celsius(v).Fahrenheit()).Celsius(), v) &&
+				approx(Kelvin(Celsius(v).Kelvin()).Celsius(), v) &&
+				approx(Celsius(Kelvin(v).Celsius()).Kelvin(), v) &&
+				approx(Celsius(Fahrenheit(v).Celsius()).Fahrenheit(), v)
+		}
+		if err := quick.Check(roundTrip, nil); err != nil {
+			t.Errorf("legacy=%v: %v", legacy, err)
+		}
+	}
+	LegacyKelvin = false
+}
+
+func TestSpeed_RoundTrip(t *testing.T) {
+	if ms := KilometersPerHour(36).MetersPerSecond(); ms != 10 {
+		t.Fatalf("expected 10 m/s, got %v", ms)
+	}
+	if kph := MilesPerHour(1).KilometersPerHour(); !approx(kph, 1.609344) {
+		t.Fatalf("expected 1.609344 km/h, got %v", kph)
+	}
+
+	roundTrip := func(v float64) bool {
+		v = bounded(v)
+		return approx(KilometersPerHour(v).KilometersPerHour(), v) &&
+			approx(MilesPerHour(KilometersPerHour(v).MilesPerHour()).KilometersPerHour(), v) &&
+			approx(MetersPerSecond(MilesPerHour(v).MetersPerSecond()).MilesPerHour(), v)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPressure_RoundTrip(t *testing.T) {
+	if inHg := Hectopascals(1013.25).InchesOfMercury(); math.Abs(inHg-29.921) > 0.001 {
+		t.Fatalf("expected ~29.92 inHg, got %v", inHg)
+	}
+
+	roundTrip := func(v float64) bool {
+		v = bounded(v)
+		return approx(InchesOfMercury(Hectopascals(v).InchesOfMercury()).Hectopascals(), v) &&
+			approx(Pascals(Hectopascals(v).Pascals()).Hectopascals(), v)
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	if r := Round(20*1.8 + 32.00000000000001); r != 68 {
+		t.Fatalf("expected 68, got %v", r)
+	}
+	if r := RoundTo(71.60000000000001, 1); r != 71.6 {
+		t.Fatalf("expected 71.6, got %v", r)
+	}
+	if r := RoundTo(1.23456, -1); r != 1.23456 {
+		t.Fatalf("expected unrounded value, got %v", r)
+	}
+
+	// Arredondar é idempotente e nunca desloca o valor em mais de meia casa
+	idempotent := func(v float64, p uint8) bool {
+		v, precision := bounded(v), int(p%6)
+		r := RoundTo(v, precision)
+		return RoundTo(r, precision) == r && math.Abs(r-v) <= 0.5*math.Pow10(-precision)+1e-9
+	}
+	if err := quick.Check(idempotent, nil); err != nil {
+		t.Error(err)
+	}
+}
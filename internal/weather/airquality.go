@@ -0,0 +1,114 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// AirQualityURL é a URL da API WeatherAPI para consulta das condições atuais
+// com os dados de qualidade do ar (aqi=yes)
+// Pode ser sobrescrita para fins de teste
+// Formato: https://api.weatherapi.com/v1/current.json?key={API_KEY}&q={CITY}&aqi=yes
+var AirQualityURL = "https://api.weatherapi.com/v1/current.json?key=%s&q=%s&aqi=yes"
+
+// AirQuality representa a qualidade do ar atual em uma cidade
+// As concentrações dos poluentes são expressas em μg/m³
+type AirQuality struct {
+	PM25       float64 // Material particulado fino (PM2.5)
+	PM10       float64 // Material particulado inalável (PM10)
+	O3         float64 // Ozônio
+	USEPAIndex int     // Índice de qualidade do ar da US EPA (1 a 6)
+	UV         float64 // Índice UV
+}
+
+// airQualityResponse representa a estrutura de resposta da API WeatherAPI (current.json com aqi=yes)
+// Exemplo de resposta:
+//
+//	{
+//	  "current": {
+//	    "uv": 6.0,
+//	    "air_quality": {"pm2_5": 8.2, "pm10": 12.3, "o3": 56.1, "us-epa-index": 1, ...}
+//	  }
+//	}
+type airQualityResponse struct {
+	Current struct {
+		UV         float64 `json:"uv"`
+		AirQuality *struct {
+			PM25       float64 `json:"pm2_5"`
+			PM10       float64 `json:"pm10"`
+			O3         float64 `json:"o3"`
+			USEPAIndex int     `json:"us-epa-index"`
+		} `json:"air_quality"`
+	} `json:"current"`
+}
+
+// GetAirQuality consulta a WeatherAPI para obter a qualidade do ar atual para uma cidade
+//
+// Assim como GetConditions, cria um span próprio ("weatherapi-air-quality-call")
+// para medir o tempo de resposta da chamada externa.
+//
+// Parâmetros:
+//   - ctx: Contexto com informações de rastreamento distribuído (spans)
+//   - city: Nome da cidade
+//
+// Retorna:
+//   - AirQuality: Concentração de PM2.5, PM10 e O3, índice US EPA e índice UV
+//   - error: Erro caso a consulta falhe ou a resposta não traga os dados de qualidade do ar
+func GetAirQuality(ctx context.Context, city string) (AirQuality, error) {
+	tracer := otel.Tracer("weather-service")
+	ctx, span := tracer.Start(ctx, "weatherapi-air-quality-call")
+	defer span.End()
+
+	// fail registra o erro no span antes de devolvê-lo
+	fail := func(err error) (AirQuality, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return AirQuality{}, err
+	}
+
+	span.SetAttributes(attribute.String("weatherapi.city", city))
+
+	resp, err := callWithKey(ctx, func(key string) string {
+		return fmt.Sprintf(AirQualityURL, key, url.QueryEscape(city))
+	})
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("http.status_code", int64(resp.StatusCode)))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		log.Printf("Falha na consulta da qualidade do ar: status %d, resposta: %s", resp.StatusCode, string(body))
+		return fail(fmt.Errorf("air quality lookup failed"))
+	}
+
+	var raw airQualityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fail(err)
+	}
+	// Sem aqi=yes (ou em planos que não incluem o dado) o campo não é enviado
+	aq := raw.Current.AirQuality
+	if aq == nil {
+		return fail(fmt.Errorf("air quality data missing from response"))
+	}
+
+	span.SetAttributes(attribute.Int("weather.us_epa_index", aq.USEPAIndex))
+	span.SetStatus(codes.Ok, "")
+	return AirQuality{
+		PM25:       aq.PM25,
+		PM10:       aq.PM10,
+		O3:         aq.O3,
+		USEPAIndex: aq.USEPAIndex,
+		UV:         raw.Current.UV,
+	}, nil
+}
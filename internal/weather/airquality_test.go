@@ -0,0 +1,47 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAirQuality_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"current":{"uv":6,"air_quality":{"co":230.3,"o3":56.1,"pm2_5":8.2,"pm10":12.3,"us-epa-index":2,"gb-defra-index":1}}}`)
+	}))
+	defer srv.Close()
+
+	origURL := AirQualityURL
+	AirQualityURL = srv.URL + "/?key=%s&q=%s&aqi=yes"
+	defer func() { AirQualityURL = origURL }()
+	setTestKey(t, "testkey")
+
+	aq, err := GetAirQuality(context.Background(), "Linhares")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := AirQuality{PM25: 8.2, PM10: 12.3, O3: 56.1, USEPAIndex: 2, UV: 6}
+	if aq != want {
+		t.Fatalf("expected %+v, got %+v", want, aq)
+	}
+}
+
+func TestGetAirQuality_Missing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"current":{"uv":6}}`)
+	}))
+	defer srv.Close()
+
+	origURL := AirQualityURL
+	AirQualityURL = srv.URL + "/?key=%s&q=%s&aqi=yes"
+	defer func() { AirQualityURL = origURL }()
+	setTestKey(t, "testkey")
+
+	if _, err := GetAirQuality(context.Background(), "Linhares"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
@@ -0,0 +1,148 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// AlertsURL é a URL da API WeatherAPI para consulta dos alertas meteorológicos
+// Os alertas só são enviados pelo endpoint de previsão, com alerts=yes
+// Pode ser sobrescrita para fins de teste
+// Formato: https://api.weatherapi.com/v1/forecast.json?key={API_KEY}&q={CITY}&days=1&alerts=yes
+var AlertsURL = "https://api.weatherapi.com/v1/forecast.json?key=%s&q=%s&days=1&alerts=yes"
+
+// Níveis de severidade dos alertas, conforme o padrão CAP (Common Alerting Protocol)
+const (
+	SeverityExtreme  = "extreme"
+	SeveritySevere   = "severe"
+	SeverityModerate = "moderate"
+	SeverityMinor    = "minor"
+	SeverityUnknown  = "unknown"
+)
+
+// Alert representa um alerta meteorológico ativo
+type Alert struct {
+	Event       string    // Tipo do evento (ex: "Heavy Rain")
+	Severity    string    // Severidade normalizada (ver Severity*)
+	Headline    string    // Resumo do alerta
+	Start       time.Time // Início da vigência (zero quando não informado)
+	End         time.Time // Fim da vigência (zero quando não informado)
+	Description string    // Descrição completa
+}
+
+// alertsResponse representa a estrutura de resposta da API WeatherAPI (forecast.json com alerts=yes)
+// Exemplo de resposta:
+//
+//	{
+//	  "alerts": {
+//	    "alert": [
+//	      {"headline": "...", "severity": "Severe", "event": "Heavy Rain",
+//	       "effective": "2024-05-01T08:00:00-03:00", "expires": "2024-05-02T08:00:00-03:00", "desc": "..."}
+//	    ]
+//	  }
+//	}
+type alertsResponse struct {
+	Alerts struct {
+		Alert []struct {
+			Headline  string `json:"headline"`
+			Severity  string `json:"severity"`
+			Event     string `json:"event"`
+			Effective string `json:"effective"`
+			Expires   string `json:"expires"`
+			Desc      string `json:"desc"`
+		} `json:"alert"`
+	} `json:"alerts"`
+}
+
+// normalizeSeverity converte a severidade informada pela WeatherAPI para os
+// níveis do padrão CAP; valores ausentes ou desconhecidos resultam em SeverityUnknown
+func normalizeSeverity(s string) string {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case SeverityExtreme, SeveritySevere, SeverityModerate, SeverityMinor:
+		return s
+	default:
+		return SeverityUnknown
+	}
+}
+
+// parseTime interpreta os horários dos alertas (RFC 3339); valores inválidos resultam em zero
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// GetAlerts consulta a WeatherAPI para obter os alertas meteorológicos ativos para uma cidade
+//
+// Assim como GetForecast, cria um span próprio ("weatherapi-alerts-call")
+// para medir o tempo de resposta da chamada externa.
+//
+// Parâmetros:
+//   - ctx: Contexto com informações de rastreamento distribuído (spans)
+//   - city: Nome da cidade
+//
+// Retorna:
+//   - []Alert: Alertas ativos, na ordem informada pela WeatherAPI (vazio quando não há alertas)
+//   - error: Erro caso a consulta falhe (API key ausente, falha na requisição, etc.)
+func GetAlerts(ctx context.Context, city string) ([]Alert, error) {
+	tracer := otel.Tracer("weather-service")
+	ctx, span := tracer.Start(ctx, "weatherapi-alerts-call")
+	defer span.End()
+
+	// fail registra o erro no span antes de devolvê-lo
+	fail := func(err error) ([]Alert, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("weatherapi.city", city))
+
+	resp, err := callWithKey(ctx, func(key string) string {
+		return fmt.Sprintf(AlertsURL, key, url.QueryEscape(city))
+	})
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("http.status_code", int64(resp.StatusCode)))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		log.Printf("Falha na consulta dos alertas: status %d, resposta: %s", resp.StatusCode, string(body))
+		return fail(fmt.Errorf("alerts lookup failed"))
+	}
+
+	var raw alertsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fail(err)
+	}
+
+	alerts := make([]Alert, 0, len(raw.Alerts.Alert))
+	for _, a := range raw.Alerts.Alert {
+		alerts = append(alerts, Alert{
+			Event:       strings.TrimSpace(a.Event),
+			Severity:    normalizeSeverity(a.Severity),
+			Headline:    strings.TrimSpace(a.Headline),
+			Start:       parseTime(a.Effective),
+			End:         parseTime(a.Expires),
+			Description: strings.TrimSpace(a.Desc),
+		})
+	}
+	span.SetAttributes(attribute.Int("weather.alerts", len(alerts)))
+	span.SetStatus(codes.Ok, "")
+	return alerts, nil
+}
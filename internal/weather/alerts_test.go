@@ -0,0 +1,61 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAlerts_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"alerts":{"alert":[
+			{"headline":"Chuvas intensas","severity":"Severe","event":"Heavy Rain ","effective":"2024-05-01T08:00:00-03:00","expires":"2024-05-02T08:00:00-03:00","desc":"Chuva entre 30 e 60 mm/h."},
+			{"headline":"Aviso","severity":"","event":"Wind","effective":"","expires":"invalid","desc":""}
+		]}}`)
+	}))
+	defer srv.Close()
+
+	origURL := AlertsURL
+	AlertsURL = srv.URL + "/?key=%s&q=%s"
+	defer func() { AlertsURL = origURL }()
+	setTestKey(t, "testkey")
+
+	alerts, err := GetAlerts(context.Background(), "Linhares")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(alerts))
+	}
+	a := alerts[0]
+	if a.Event != "Heavy Rain" || a.Severity != SeveritySevere || a.Description != "Chuva entre 30 e 60 mm/h." {
+		t.Errorf("unexpected alert: %+v", a)
+	}
+	if !a.Start.Equal(time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)) || !a.End.Equal(time.Date(2024, 5, 2, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected validity: %v - %v", a.Start, a.End)
+	}
+	// Severidade ausente e horários inválidos não invalidam o alerta
+	if alerts[1].Severity != SeverityUnknown || !alerts[1].Start.IsZero() || !alerts[1].End.IsZero() {
+		t.Errorf("unexpected normalization: %+v", alerts[1])
+	}
+}
+
+func TestGetAlerts_Empty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"alerts":{"alert":[]}}`)
+	}))
+	defer srv.Close()
+
+	origURL := AlertsURL
+	AlertsURL = srv.URL + "/?key=%s&q=%s"
+	defer func() { AlertsURL = origURL }()
+	setTestKey(t, "testkey")
+
+	alerts, err := GetAlerts(context.Background(), "Linhares")
+	if err != nil || alerts == nil || len(alerts) != 0 {
+		t.Fatalf("expected empty non-nil list, got %v (%v)", alerts, err)
+	}
+}
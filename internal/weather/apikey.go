@@ -0,0 +1,110 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// configuredPool guarda o pool de chaves da WeatherAPI definido pela configuração do serviço
+var configuredPool atomic.Pointer[KeyPool]
+
+// keyUsage conta as chamadas à WeatherAPI por chave (atributo weatherapi.key_id)
+var keyUsage, _ = otel.Meter("weather-service").Int64Counter("weatherapi.key.calls",
+	metric.WithDescription("Chamadas à WeatherAPI por chave"))
+
+// SetKeyPool define o pool de chaves usado nas chamadas à WeatherAPI
+// Os serviços o definem a partir da configuração validada (ver pacote config),
+// evitando a leitura do ambiente a cada requisição. A troca é atômica e pode
+// ocorrer com requisições em andamento: cada chamada escolhe a chave uma única
+// vez, no início, e a conclui com ela
+func SetKeyPool(p *KeyPool) {
+	configuredPool.Store(p)
+}
+
+// SetAPIKey define uma única chave para as chamadas à WeatherAPI
+// Atalho para SetKeyPool com um pool de uma chave; "" remove as chaves, e as
+// chamadas seguintes falham com ErrNoAPIKey
+func SetAPIKey(key string) {
+	SetKeyPool(NewKeyPool([]string{key}, RoundRobin, DefaultQuarantine))
+}
+
+// noKeys é o pool vazio usado antes de SetKeyPool: as chamadas falham com ErrNoAPIKey
+var noKeys = NewKeyPool(nil, RoundRobin, DefaultQuarantine)
+
+// currentPool retorna o pool configurado por SetKeyPool
+// O ambiente não é consultado: a chave vem apenas da configuração do serviço
+func currentPool() *KeyPool {
+	if p := configuredPool.Load(); p != nil {
+		return p
+	}
+	return noKeys
+}
+
+// useKey escolhe a chave de uma chamada à WeatherAPI
+// O span atual recebe apenas o identificador da chave (ver KeyID), e o uso é
+// contabilizado na métrica weatherapi.key.calls
+func useKey(ctx context.Context) (*poolKey, error) {
+	key, err := currentPool().acquire()
+	if err != nil {
+		return nil, err
+	}
+	id := attribute.String("weatherapi.key_id", key.id)
+	trace.SpanFromContext(ctx).SetAttributes(id)
+	keyUsage.Add(ctx, 1, metric.WithAttributes(id))
+	return key, nil
+}
+
+// finish registra o status da resposta obtida com a chave
+// Quando a chave é colocada em quarentena, o span atual recebe um evento e o
+// retorno é true
+func (k *poolKey) finish(ctx context.Context, status int) bool {
+	if !k.report(status) {
+		return false
+	}
+	trace.SpanFromContext(ctx).AddEvent("weatherapi.key_quarantined", trace.WithAttributes(
+		attribute.String("weatherapi.key_id", k.id),
+		attribute.Int("http.status_code", status),
+	))
+	return true
+}
+
+// callWithKey faz o GET à WeatherAPI com uma chave do pool
+//
+// Cada tentativa aguarda o limitador de taxa (ver SetLimiter) e escolhe a
+// chave com useKey; urlFor monta a URL com a chave escolhida. Quando a
+// WeatherAPI recusa a chave (401 ou 403), ela entra em quarentena e a chamada
+// é repetida com a próxima chave, no máximo uma vez por chave do pool. Sem
+// outra tentativa possível, a resposta de recusa é devolvida ao chamador; com
+// todas as chaves em quarentena, o erro é ErrKeysQuarantined.
+//
+// O chamador deve fechar o corpo da resposta.
+func callWithKey(ctx context.Context, urlFor func(key string) string) (*http.Response, error) {
+	client := newClient()
+	for attempt := 1; ; attempt++ {
+		if err := limiter.Load().Wait(ctx); err != nil {
+			return nil, err
+		}
+		key, err := useKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlFor(key.value), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !key.finish(ctx, resp.StatusCode) || attempt >= currentPool().Len() {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+}
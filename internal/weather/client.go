@@ -0,0 +1,77 @@
+package weather
+
+import (
+	"cep-weather/internal/ratelimit"
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// limiter limita a taxa de chamadas à WeatherAPI (nil não limita)
+var limiter atomic.Pointer[ratelimit.Limiter]
+
+// SetLimiter define o limitador de taxa das chamadas à WeatherAPI
+// Cada chamada obtém um token antes de escolher a chave; nil remove o limite
+func SetLimiter(l *ratelimit.Limiter) {
+	limiter.Store(l)
+}
+
+// keyContextKey guarda no contexto da requisição a chave removida da URL
+type keyContextKey struct{}
+
+// newClient cria o cliente HTTP instrumentado das chamadas à WeatherAPI
+//
+// O transporte do OpenTelemetry registra a URL completa da requisição no span;
+// como a WeatherAPI recebe a chave na query string ("key"), ela é removida da
+// URL antes do transporte instrumentado e devolvida logo antes do envio. Assim,
+// os spans identificam a chave apenas pelo hash (ver KeyID).
+func newClient() *http.Client {
+	return &http.Client{
+		Transport: hideKey{next: otelhttp.NewTransport(restoreKey{next: http.DefaultTransport})},
+	}
+}
+
+// hideKey remove a chave da URL e a guarda no contexto da requisição
+type hideKey struct{ next http.RoundTripper }
+
+func (t hideKey) RoundTrip(r *http.Request) (*http.Response, error) {
+	q := r.URL.Query()
+	key := q.Get("key")
+	if key == "" {
+		return t.next.RoundTrip(r)
+	}
+	q.Del("key")
+	r = r.Clone(context.WithValue(r.Context(), keyContextKey{}, key))
+	r.URL.RawQuery = q.Encode()
+	return t.next.RoundTrip(r)
+}
+
+// restoreKey devolve à URL a chave guardada por hideKey
+type restoreKey struct{ next http.RoundTripper }
+
+func (t restoreKey) RoundTrip(r *http.Request) (*http.Response, error) {
+	key, _ := r.Context().Value(keyContextKey{}).(string)
+	if key == "" {
+		return t.next.RoundTrip(r)
+	}
+	r = r.Clone(r.Context())
+	q := r.URL.Query()
+	q.Set("key", key)
+	r.URL.RawQuery = q.Encode()
+	return t.next.RoundTrip(r)
+}
+
+// redactURL remove a chave da URL, para uso em logs e atributos de span
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Del("key")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,118 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ForecastURL é a URL da API WeatherAPI para consulta da previsão diária
+// Pode ser sobrescrita para fins de teste
+// Formato: https://api.weatherapi.com/v1/forecast.json?key={API_KEY}&q={CITY}&days={DAYS}
+var ForecastURL = "https://api.weatherapi.com/v1/forecast.json?key=%s&q=%s&days=%d"
+
+// ForecastDay representa a previsão de um dia
+type ForecastDay struct {
+	Date         string  // Data no formato AAAA-MM-DD, no fuso horário da cidade
+	MinTempC     float64 // Temperatura mínima em graus Celsius
+	MaxTempC     float64 // Temperatura máxima em graus Celsius
+	ChanceOfRain int     // Probabilidade de chuva, em porcentagem (0 a 100)
+	PrecipMM     float64 // Precipitação total prevista, em milímetros
+}
+
+// forecastResponse representa a estrutura de resposta da API WeatherAPI (forecast.json)
+// Exemplo de resposta:
+//
+//	{
+//	  "forecast": {
+//	    "forecastday": [
+//	      {"date": "2024-05-01", "day": {"maxtemp_c": 31.2, "mintemp_c": 22.4, "daily_chance_of_rain": 80, "totalprecip_mm": 4.1}}
+//	    ]
+//	  }
+//	}
+type forecastResponse struct {
+	Forecast struct {
+		ForecastDay []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxTempC     float64 `json:"maxtemp_c"`
+				MinTempC     float64 `json:"mintemp_c"`
+				ChanceOfRain int     `json:"daily_chance_of_rain"`
+				PrecipMM     float64 `json:"totalprecip_mm"`
+			} `json:"day"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+}
+
+// GetForecast consulta a WeatherAPI para obter a previsão dos próximos dias para uma cidade
+//
+// Assim como GetTemperature, cria um span próprio ("weatherapi-forecast-call")
+// para medir o tempo de resposta da chamada externa.
+//
+// Parâmetros:
+//   - ctx: Contexto com informações de rastreamento distribuído (spans)
+//   - city: Nome da cidade
+//   - days: Quantidade de dias de previsão, a partir de hoje
+//
+// Retorna:
+//   - []ForecastDay: Previsão de cada dia, em ordem cronológica
+//   - error: Erro caso a consulta falhe (API key ausente, falha na requisição, etc.)
+func GetForecast(ctx context.Context, city string, days int) ([]ForecastDay, error) {
+	tracer := otel.Tracer("weather-service")
+	ctx, span := tracer.Start(ctx, "weatherapi-forecast-call")
+	defer span.End()
+
+	// fail registra o erro no span antes de devolvê-lo
+	fail := func(err error) ([]ForecastDay, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("weatherapi.city", city),
+		attribute.Int("weatherapi.days", days),
+	)
+
+	resp, err := callWithKey(ctx, func(key string) string {
+		return fmt.Sprintf(ForecastURL, key, url.QueryEscape(city), days)
+	})
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("http.status_code", int64(resp.StatusCode)))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		log.Printf("Falha na consulta da previsão: status %d, resposta: %s", resp.StatusCode, string(body))
+		return fail(fmt.Errorf("forecast lookup failed"))
+	}
+
+	var raw forecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fail(err)
+	}
+
+	forecast := make([]ForecastDay, 0, len(raw.Forecast.ForecastDay))
+	for _, d := range raw.Forecast.ForecastDay {
+		forecast = append(forecast, ForecastDay{
+			Date:         d.Date,
+			MinTempC:     d.Day.MinTempC,
+			MaxTempC:     d.Day.MaxTempC,
+			ChanceOfRain: d.Day.ChanceOfRain,
+			PrecipMM:     d.Day.PrecipMM,
+		})
+	}
+	span.SetAttributes(attribute.Int("weather.forecast_days", len(forecast)))
+	span.SetStatus(codes.Ok, "")
+	return forecast, nil
+}
@@ -0,0 +1,59 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetForecast_Success(t *testing.T) {
+	var days string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		days = r.URL.Query().Get("days")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"forecast":{"forecastday":[
+			{"date":"2024-05-01","day":{"maxtemp_c":31.2,"mintemp_c":22.4,"daily_chance_of_rain":80,"totalprecip_mm":4.1}},
+			{"date":"2024-05-02","day":{"maxtemp_c":29,"mintemp_c":21,"daily_chance_of_rain":0,"totalprecip_mm":0}}
+		]}}`)
+	}))
+	defer srv.Close()
+
+	origURL := ForecastURL
+	ForecastURL = srv.URL + "/?key=%s&q=%s&days=%d"
+	defer func() { ForecastURL = origURL }()
+	setTestKey(t, "testkey")
+
+	forecast, err := GetForecast(context.Background(), "Linhares", 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if days != "2" {
+		t.Errorf("expected days=2 to be requested, got %q", days)
+	}
+	if len(forecast) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(forecast))
+	}
+	want := ForecastDay{Date: "2024-05-01", MinTempC: 22.4, MaxTempC: 31.2, ChanceOfRain: 80, PrecipMM: 4.1}
+	if forecast[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, forecast[0])
+	}
+}
+
+func TestGetForecast_ApiError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"error":{"message":"No matching location found."}}`)
+	}))
+	defer srv.Close()
+
+	origURL := ForecastURL
+	ForecastURL = srv.URL + "/?key=%s&q=%s&days=%d"
+	defer func() { ForecastURL = origURL }()
+	setTestKey(t, "testkey")
+
+	if _, err := GetForecast(context.Background(), "Nowhere", 3); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
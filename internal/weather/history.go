@@ -0,0 +1,139 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// HistoryURL é a URL da API WeatherAPI para consulta do histórico de um dia
+// Pode ser sobrescrita para fins de teste
+// Formato: https://api.weatherapi.com/v1/history.json?key={API_KEY}&q={CITY}&dt={AAAA-MM-DD}
+var HistoryURL = "https://api.weatherapi.com/v1/history.json?key=%s&q=%s&dt=%s"
+
+// HistoryDay representa as temperaturas registradas em um dia passado
+type HistoryDay struct {
+	Date     string        // Data no formato AAAA-MM-DD, no fuso horário da cidade
+	TimeZone string        // Fuso horário IANA informado pela WeatherAPI (ex: "America/Sao_Paulo")
+	MinTempC float64       // Temperatura mínima em graus Celsius
+	MaxTempC float64       // Temperatura máxima em graus Celsius
+	AvgTempC float64       // Temperatura média em graus Celsius
+	Hours    []HistoryHour // Temperatura de cada hora do dia, em ordem cronológica
+}
+
+// HistoryHour representa a temperatura registrada em uma hora do dia
+type HistoryHour struct {
+	Time  string  // Horário local no formato "AAAA-MM-DD HH:MM"
+	TempC float64 // Temperatura em graus Celsius
+}
+
+// historyResponse representa a estrutura de resposta da API WeatherAPI (history.json)
+// Exemplo de resposta:
+//
+//	{
+//	  "location": {"tz_id": "America/Sao_Paulo", ...},
+//	  "forecast": {
+//	    "forecastday": [
+//	      {"date": "2024-05-01", "day": {"maxtemp_c": 31.2, "mintemp_c": 22.4, "avgtemp_c": 26.1},
+//	       "hour": [{"time": "2024-05-01 00:00", "temp_c": 23.0}, ...]}
+//	    ]
+//	  }
+//	}
+type historyResponse struct {
+	Location struct {
+		TzID string `json:"tz_id"`
+	} `json:"location"`
+	Forecast struct {
+		ForecastDay []struct {
+			Date string `json:"date"`
+			Day  struct {
+				MaxTempC float64 `json:"maxtemp_c"`
+				MinTempC float64 `json:"mintemp_c"`
+				AvgTempC float64 `json:"avgtemp_c"`
+			} `json:"day"`
+			Hour []struct {
+				Time  string  `json:"time"`
+				TempC float64 `json:"temp_c"`
+			} `json:"hour"`
+		} `json:"forecastday"`
+	} `json:"forecast"`
+}
+
+// GetHistory consulta a WeatherAPI para obter as temperaturas registradas em
+// um dia passado para uma cidade
+//
+// Assim como GetForecast, cria um span próprio ("weatherapi-history-call")
+// para medir o tempo de resposta da chamada externa.
+//
+// Parâmetros:
+//   - ctx: Contexto com informações de rastreamento distribuído (spans)
+//   - city: Nome da cidade
+//   - date: Data no formato AAAA-MM-DD, no fuso horário da cidade
+//
+// Retorna:
+//   - HistoryDay: Temperaturas mínima, máxima e média do dia, e de cada hora
+//   - error: Erro caso a consulta falhe ou a resposta não traga o dia pedido
+func GetHistory(ctx context.Context, city, date string) (HistoryDay, error) {
+	tracer := otel.Tracer("weather-service")
+	ctx, span := tracer.Start(ctx, "weatherapi-history-call")
+	defer span.End()
+
+	// fail registra o erro no span antes de devolvê-lo
+	fail := func(err error) (HistoryDay, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return HistoryDay{}, err
+	}
+
+	span.SetAttributes(
+		attribute.String("weatherapi.city", city),
+		attribute.String("weatherapi.date", date),
+	)
+
+	resp, err := callWithKey(ctx, func(key string) string {
+		return fmt.Sprintf(HistoryURL, key, url.QueryEscape(city), url.QueryEscape(date))
+	})
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int64("http.status_code", int64(resp.StatusCode)))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		log.Printf("Falha na consulta do histórico: status %d, resposta: %s", resp.StatusCode, string(body))
+		return fail(fmt.Errorf("history lookup failed"))
+	}
+
+	var raw historyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fail(err)
+	}
+	if len(raw.Forecast.ForecastDay) == 0 || raw.Forecast.ForecastDay[0].Date != date {
+		return fail(fmt.Errorf("history for %s missing from response", date))
+	}
+
+	d := raw.Forecast.ForecastDay[0]
+	day := HistoryDay{
+		Date:     d.Date,
+		TimeZone: raw.Location.TzID,
+		MinTempC: d.Day.MinTempC,
+		MaxTempC: d.Day.MaxTempC,
+		AvgTempC: d.Day.AvgTempC,
+		Hours:    make([]HistoryHour, len(d.Hour)),
+	}
+	for i, h := range d.Hour {
+		day.Hours[i] = HistoryHour{Time: h.Time, TempC: h.TempC}
+	}
+	span.SetAttributes(attribute.String("weatherapi.tz_id", day.TimeZone))
+	span.SetStatus(codes.Ok, "")
+	return day, nil
+}
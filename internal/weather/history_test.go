@@ -0,0 +1,56 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetHistory_Success(t *testing.T) {
+	var dt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dt = r.URL.Query().Get("dt")
+		fmt.Fprintln(w, `{"location":{"tz_id":"America/Sao_Paulo"},"forecast":{"forecastday":[
+			{"date":"2024-05-01","day":{"maxtemp_c":31.2,"mintemp_c":22.4,"avgtemp_c":26.1},
+			 "hour":[{"time":"2024-05-01 00:00","temp_c":23},{"time":"2024-05-01 01:00","temp_c":22.5}]}
+		]}}`)
+	}))
+	defer srv.Close()
+
+	origURL := HistoryURL
+	HistoryURL = srv.URL + "/?key=%s&q=%s&dt=%s"
+	defer func() { HistoryURL = origURL }()
+	setTestKey(t, "testkey")
+
+	day, err := GetHistory(context.Background(), "Linhares", "2024-05-01")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dt != "2024-05-01" {
+		t.Errorf("expected dt=2024-05-01 to be requested, got %q", dt)
+	}
+	if day.TimeZone != "America/Sao_Paulo" || day.MinTempC != 22.4 || day.MaxTempC != 31.2 || day.AvgTempC != 26.1 {
+		t.Errorf("unexpected day: %+v", day)
+	}
+	if len(day.Hours) != 2 || day.Hours[1] != (HistoryHour{Time: "2024-05-01 01:00", TempC: 22.5}) {
+		t.Errorf("unexpected hours: %+v", day.Hours)
+	}
+}
+
+func TestGetHistory_MissingDay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"location":{"tz_id":"America/Sao_Paulo"},"forecast":{"forecastday":[]}}`)
+	}))
+	defer srv.Close()
+
+	origURL := HistoryURL
+	HistoryURL = srv.URL + "/?key=%s&q=%s&dt=%s"
+	defer func() { HistoryURL = origURL }()
+	setTestKey(t, "testkey")
+
+	if _, err := GetHistory(context.Background(), "Linhares", "2024-05-01"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
@@ -0,0 +1,157 @@
+package weather
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Strategy define como o pool escolhe a chave de cada chamada
+type Strategy string
+
+const (
+	RoundRobin Strategy = "round-robin" // Chaves usadas em sequência
+	LeastUsed  Strategy = "least-used"  // Chave com menos chamadas até o momento
+)
+
+// DefaultQuarantine é o tempo padrão de quarentena das chaves recusadas pela WeatherAPI
+const DefaultQuarantine = time.Hour
+
+var (
+	// ErrNoAPIKey indica que nenhuma chave da WeatherAPI foi configurada (ver SetKeyPool)
+	ErrNoAPIKey = errors.New("WEATHER_API_KEY not set")
+	// ErrKeysQuarantined indica que todas as chaves estão em quarentena
+	ErrKeysQuarantined = errors.New("all WeatherAPI keys are quarantined")
+)
+
+// KeyPool distribui as chamadas à WeatherAPI entre várias chaves
+//
+// O plano gratuito da WeatherAPI tem cota mensal por chave: com várias chaves,
+// a carga é dividida entre elas conforme a estratégia escolhida. Chaves
+// recusadas pela WeatherAPI (401 ou 403, ex: revogadas ou com a cota esgotada)
+// ficam em quarentena e deixam de ser usadas até o fim do prazo.
+//
+// O KeyPool é seguro para uso concorrente.
+type KeyPool struct {
+	mu         sync.Mutex
+	keys       []*poolKey
+	strategy   Strategy
+	quarantine time.Duration
+	next       int              // Próxima posição no rodízio (RoundRobin)
+	now        func() time.Time // Relógio, substituível em testes
+}
+
+// poolKey é uma chave do pool com o seu uso e a quarentena
+type poolKey struct {
+	pool  *KeyPool
+	value string
+	id    string    // Identificador seguro para logs, spans e métricas (ver KeyID)
+	uses  int64     // Chamadas feitas com a chave
+	until time.Time // Fim da quarentena (zero quando a chave está disponível)
+}
+
+// KeyID retorna o identificador de uma chave: o início do seu hash SHA-256
+// Permite distinguir as chaves em logs, spans e métricas sem expô-las
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
+// NewKeyPool cria o pool com as chaves informadas
+// Chaves vazias ou repetidas são ignoradas
+//
+// Parâmetros:
+//   - keys: Chaves da WeatherAPI
+//   - strategy: Estratégia de escolha (RoundRobin ou LeastUsed)
+//   - quarantine: Tempo de quarentena das chaves recusadas (0 usa DefaultQuarantine)
+func NewKeyPool(keys []string, strategy Strategy, quarantine time.Duration) *KeyPool {
+	if quarantine <= 0 {
+		quarantine = DefaultQuarantine
+	}
+	p := &KeyPool{strategy: strategy, quarantine: quarantine, now: time.Now}
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		p.keys = append(p.keys, &poolKey{pool: p, value: k, id: KeyID(k)})
+	}
+	return p
+}
+
+// Len retorna a quantidade de chaves do pool
+func (p *KeyPool) Len() int {
+	return len(p.keys)
+}
+
+// Usage retorna a quantidade de chamadas feitas com cada chave, por KeyID
+func (p *KeyPool) Usage() map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	usage := make(map[string]int64, len(p.keys))
+	for _, k := range p.keys {
+		usage[k.id] = k.uses
+	}
+	return usage
+}
+
+// acquire escolhe a chave da próxima chamada e contabiliza o seu uso
+//
+// Retorna ErrNoAPIKey quando o pool está vazio e ErrKeysQuarantined quando
+// todas as chaves estão em quarentena
+func (p *KeyPool) acquire() (*poolKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return nil, ErrNoAPIKey
+	}
+
+	now := p.now()
+	var chosen *poolKey
+	for i := range p.keys {
+		// O rodízio começa na posição seguinte à última chave usada
+		idx := i
+		if p.strategy != LeastUsed {
+			idx = (p.next + i) % len(p.keys)
+		}
+		k := p.keys[idx]
+		if now.Before(k.until) {
+			continue
+		}
+		if p.strategy != LeastUsed {
+			chosen, p.next = k, idx+1
+			break
+		}
+		if chosen == nil || k.uses < chosen.uses {
+			chosen = k
+		}
+	}
+	if chosen == nil {
+		return nil, ErrKeysQuarantined
+	}
+	chosen.uses++
+	return chosen, nil
+}
+
+// report registra o status da resposta obtida com a chave
+// Respostas 401 e 403 colocam a chave em quarentena
+//
+// Retorna true quando a chave foi colocada em quarentena
+func (k *poolKey) report(status int) bool {
+	if status != http.StatusUnauthorized && status != http.StatusForbidden {
+		return false
+	}
+	p := k.pool
+	p.mu.Lock()
+	k.until = p.now().Add(p.quarantine)
+	until := k.until
+	p.mu.Unlock()
+
+	log.Printf("Chave %s da WeatherAPI em quarentena até %s (status %d)", k.id, until.Format(time.RFC3339), status)
+	return true
+}
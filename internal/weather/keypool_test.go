@@ -0,0 +1,225 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// acquireN escolhe n chaves do pool e devolve os seus valores
+func acquireN(t *testing.T, p *KeyPool, n int) []string {
+	t.Helper()
+	var got []string
+	for i := 0; i < n; i++ {
+		k, err := p.acquire()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		got = append(got, k.value)
+	}
+	return got
+}
+
+func TestKeyPool_RoundRobin(t *testing.T) {
+	p := NewKeyPool([]string{"a", "b", "", "c", "a"}, RoundRobin, time.Hour)
+	if p.Len() != 3 {
+		t.Fatalf("expected empty and repeated keys to be ignored, got %d keys", p.Len())
+	}
+	if got := strings.Join(acquireN(t, p, 4), ","); got != "a,b,c,a" {
+		t.Fatalf("unexpected round-robin order %s", got)
+	}
+	if usage := p.Usage(); usage[KeyID("a")] != 2 || usage[KeyID("c")] != 1 {
+		t.Fatalf("unexpected usage %v", usage)
+	}
+}
+
+func TestKeyPool_LeastUsed(t *testing.T) {
+	p := NewKeyPool([]string{"a", "b"}, LeastUsed, time.Hour)
+	p.keys[0].uses = 3
+	if got := strings.Join(acquireN(t, p, 4), ","); got != "b,b,b,a" {
+		t.Fatalf("unexpected least-used order %s", got)
+	}
+}
+
+func TestKeyPool_Quarantine(t *testing.T) {
+	now := time.Now()
+	p := NewKeyPool([]string{"a", "b"}, RoundRobin, time.Minute)
+	p.now = func() time.Time { return now }
+
+	a, _ := p.acquire()
+	if a.report(http.StatusNotFound) {
+		t.Fatalf("expected only 401 and 403 to quarantine the key")
+	}
+	a.report(http.StatusUnauthorized)
+	if got := strings.Join(acquireN(t, p, 2), ","); got != "b,b" {
+		t.Fatalf("expected quarantined key to be skipped, got %s", got)
+	}
+
+	b, _ := p.acquire()
+	b.report(http.StatusForbidden)
+	if _, err := p.acquire(); !errors.Is(err, ErrKeysQuarantined) {
+		t.Fatalf("expected ErrKeysQuarantined, got %v", err)
+	}
+
+	// Ao fim da quarentena, as chaves voltam a ser usadas
+	now = now.Add(2 * time.Minute)
+	if _, err := p.acquire(); err != nil {
+		t.Fatalf("expected keys to leave quarantine, got %v", err)
+	}
+}
+
+func TestKeyPool_Empty(t *testing.T) {
+	if _, err := NewKeyPool(nil, RoundRobin, 0).acquire(); !errors.Is(err, ErrNoAPIKey) {
+		t.Fatalf("expected ErrNoAPIKey, got %v", err)
+	}
+}
+
+func TestGetConditions_KeyHiddenFromSpans(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		keys = append(keys, key)
+		if key == "revoked" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprintln(w, `{"current":{"temp_c":20}}`)
+	}))
+	defer srv.Close()
+
+	origURL := ApiURL
+	ApiURL = srv.URL + "/?key=%s&q=%s"
+	defer func() { ApiURL = origURL }()
+
+	rec := tracetest.NewSpanRecorder()
+	origTP := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(origTP)
+
+	SetKeyPool(NewKeyPool([]string{"revoked", "valid"}, RoundRobin, time.Hour))
+	defer SetAPIKey("")
+
+	// A primeira chamada usa a chave revogada, que entra em quarentena, e é
+	// repetida com a chave válida; as seguintes usam apenas a chave válida
+	for i := 0; i < 3; i++ {
+		if _, err := GetConditions(context.Background(), "Linhares"); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if got := strings.Join(keys, ","); got != "revoked,valid,valid,valid" {
+		t.Fatalf("unexpected keys sent to the API: %s", got)
+	}
+
+	var ids, quarantined int
+	for _, s := range rec.Ended() {
+		for _, a := range s.Attributes() {
+			if strings.Contains(a.Value.Emit(), "revoked") || strings.Contains(a.Value.Emit(), "valid") {
+				t.Errorf("span %s exposes the API key in %s=%s", s.Name(), a.Key, a.Value.Emit())
+			}
+			if a.Key == "weatherapi.key_id" {
+				ids++
+			}
+		}
+		for _, e := range s.Events() {
+			if e.Name == "weatherapi.key_quarantined" {
+				quarantined++
+			}
+		}
+	}
+	if ids != 3 || quarantined != 1 {
+		t.Fatalf("expected 3 key ids and 1 quarantine event, got %d and %d", ids, quarantined)
+	}
+}
+
+func TestCallWithKey_AllKeysRejected(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	SetKeyPool(NewKeyPool([]string{"a", "b", "c"}, RoundRobin, time.Hour))
+	defer SetAPIKey("")
+
+	// Cada chave é tentada uma vez; a última recusa é devolvida ao chamador
+	resp, err := callWithKey(context.Background(), func(key string) string {
+		return srv.URL + "/?key=" + key
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || calls != 3 {
+		t.Fatalf("expected 3 attempts ending in 401, got %d attempts and status %d", calls, resp.StatusCode)
+	}
+
+	// Com todas as chaves em quarentena, a chamada falha sem consultar a API
+	if _, err := callWithKey(context.Background(), func(key string) string {
+		return srv.URL + "/?key=" + key
+	}); !errors.Is(err, ErrKeysQuarantined) || calls != 3 {
+		t.Fatalf("expected ErrKeysQuarantined without new attempts, got %v after %d attempts", err, calls)
+	}
+}
+
+// metricReader coleta as métricas do pacote; o provedor global só pode ser
+// definido uma vez para os instrumentos criados na inicialização (keyUsage)
+var metricReader = sync.OnceValue(func() *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	return reader
+})
+
+// keyCalls retorna o valor de weatherapi.key.calls para a chave informada
+func keyCalls(t *testing.T, key string) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := metricReader().Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != "weatherapi.key.calls" || !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				if id, _ := dp.Attributes.Value(attribute.Key("weatherapi.key_id")); id.AsString() == KeyID(key) {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestKeyUsageMetric(t *testing.T) {
+	before := keyCalls(t, "metric-key")
+
+	SetKeyPool(NewKeyPool([]string{"metric-key"}, RoundRobin, time.Hour))
+	defer SetAPIKey("")
+	for i := 0; i < 3; i++ {
+		key, err := useKey(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		key.finish(context.Background(), http.StatusOK)
+	}
+
+	if got := keyCalls(t, "metric-key") - before; got != 3 {
+		t.Fatalf("expected 3 calls recorded for the key, got %d", got)
+	}
+}
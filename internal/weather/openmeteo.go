@@ -0,0 +1,143 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// GeocodingURL é a URL da API de geocodificação da Open-Meteo, usada para
+// obter as coordenadas da cidade (restrita ao Brasil)
+// Pode ser sobrescrita para fins de teste
+// Formato: https://geocoding-api.open-meteo.com/v1/search?name={CITY}&count=1&language=pt&countryCode=BR
+var GeocodingURL = "https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1&language=pt&countryCode=BR"
+
+// OpenMeteoAirQualityURL é a URL da API de qualidade do ar da Open-Meteo
+// Pode ser sobrescrita para fins de teste
+// Formato: https://air-quality-api.open-meteo.com/v1/air-quality?latitude={LAT}&longitude={LON}&current=...
+var OpenMeteoAirQualityURL = "https://air-quality-api.open-meteo.com/v1/air-quality?latitude=%.4f&longitude=%.4f&current=pm2_5,pm10,ozone,us_aqi,uv_index"
+
+// ErrCityNotFound indica que a geocodificação não encontrou a cidade
+var ErrCityNotFound = errors.New("city not found")
+
+// geocodingResponse representa a estrutura de resposta da geocodificação da Open-Meteo
+// Exemplo de resposta:
+//
+//	{"results": [{"name": "Linhares", "latitude": -19.39111, "longitude": -40.07222}]}
+type geocodingResponse struct {
+	Results []struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+// openMeteoAirQualityResponse representa a estrutura de resposta da API de
+// qualidade do ar da Open-Meteo; valores indisponíveis vêm como null
+// Exemplo de resposta:
+//
+//	{"current": {"pm2_5": 8.2, "pm10": 12.3, "ozone": 56.1, "us_aqi": 42, "uv_index": 6.0}}
+type openMeteoAirQualityResponse struct {
+	Current struct {
+		PM25    *float64 `json:"pm2_5"`
+		PM10    *float64 `json:"pm10"`
+		Ozone   *float64 `json:"ozone"`
+		USAQI   *float64 `json:"us_aqi"`
+		UVIndex *float64 `json:"uv_index"`
+	} `json:"current"`
+}
+
+// openMeteoAirQuality implementa AirQualityProvider com a Open-Meteo
+type openMeteoAirQuality struct{}
+
+func (openMeteoAirQuality) Name() string { return "open-meteo" }
+
+// AirQuality consulta a Open-Meteo para obter a qualidade do ar atual para uma cidade
+//
+// A Open-Meteo consulta por coordenadas: a cidade é geocodificada antes, no
+// mesmo span ("open-meteo-air-quality-call"). O índice US AQI (0 a 500) é
+// convertido na faixa da US EPA (1 a 6), a mesma informada pela WeatherAPI.
+func (openMeteoAirQuality) AirQuality(ctx context.Context, city string) (AirQuality, error) {
+	tracer := otel.Tracer("weather-service")
+	ctx, span := tracer.Start(ctx, "open-meteo-air-quality-call")
+	defer span.End()
+
+	// fail registra o erro no span antes de devolvê-lo
+	fail := func(err error) (AirQuality, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return AirQuality{}, err
+	}
+
+	span.SetAttributes(attribute.String("open_meteo.city", city))
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+	var geo geocodingResponse
+	if err := getJSON(ctx, client, fmt.Sprintf(GeocodingURL, url.QueryEscape(city)), &geo); err != nil {
+		return fail(fmt.Errorf("geocoding: %w", err))
+	}
+	if len(geo.Results) == 0 {
+		return fail(ErrCityNotFound)
+	}
+	lat, lon := geo.Results[0].Latitude, geo.Results[0].Longitude
+	span.SetAttributes(attribute.Float64("geo.lat", lat), attribute.Float64("geo.lon", lon))
+
+	var raw openMeteoAirQualityResponse
+	if err := getJSON(ctx, client, fmt.Sprintf(OpenMeteoAirQualityURL, lat, lon), &raw); err != nil {
+		return fail(err)
+	}
+	cur := raw.Current
+	if cur.PM25 == nil || cur.PM10 == nil || cur.Ozone == nil || cur.USAQI == nil {
+		return fail(fmt.Errorf("air quality data missing from response"))
+	}
+
+	index := usEPAIndex(*cur.USAQI)
+	span.SetAttributes(attribute.Int("weather.us_epa_index", index))
+	span.SetStatus(codes.Ok, "")
+	aq := AirQuality{PM25: *cur.PM25, PM10: *cur.PM10, O3: *cur.Ozone, USEPAIndex: index}
+	if cur.UVIndex != nil {
+		aq.UV = *cur.UVIndex
+	}
+	return aq, nil
+}
+
+// getJSON executa um GET e decodifica a resposta JSON em v
+func getJSON(ctx context.Context, client *http.Client, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		log.Printf("Falha na consulta à Open-Meteo: status %d, resposta: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("open-meteo request failed with status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// usEPAIndex converte o US AQI (0 a 500) na faixa da US EPA (1 a 6):
+// 0-50 bom, 51-100 moderado, 101-150 insalubre para grupos sensíveis,
+// 151-200 insalubre, 201-300 muito insalubre e acima de 300 perigoso
+func usEPAIndex(aqi float64) int {
+	for i, limit := range []float64{50, 100, 150, 200, 300} {
+		if aqi <= limit {
+			return i + 1
+		}
+	}
+	return 6
+}
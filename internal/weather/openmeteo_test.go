@@ -0,0 +1,84 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeOpenMeteo aponta as URLs da Open-Meteo para um servidor falso que
+// geocodifica apenas "Linhares" e responde com o US AQI informado
+func fakeOpenMeteo(t *testing.T, aqi string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			if r.URL.Query().Get("name") != "Linhares" {
+				fmt.Fprint(w, `{}`)
+				return
+			}
+			fmt.Fprint(w, `{"results":[{"name":"Linhares","latitude":-19.39111,"longitude":-40.07222}]}`)
+		case "/air-quality":
+			if r.URL.Query().Get("latitude") != "-19.3911" {
+				http.Error(w, "unexpected coordinates", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"current":{"pm2_5":8.2,"pm10":12.3,"ozone":56.1,"us_aqi":%s,"uv_index":6}}`, aqi)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	origGeo, origAQ := GeocodingURL, OpenMeteoAirQualityURL
+	GeocodingURL = srv.URL + "/search?name=%s"
+	OpenMeteoAirQualityURL = srv.URL + "/air-quality?latitude=%.4f&longitude=%.4f"
+	t.Cleanup(func() { GeocodingURL, OpenMeteoAirQualityURL = origGeo, origAQ })
+}
+
+func TestOpenMeteoAirQuality(t *testing.T) {
+	fakeOpenMeteo(t, "75")
+
+	aq, err := OpenMeteoAirQuality.AirQuality(context.Background(), "Linhares")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := AirQuality{PM25: 8.2, PM10: 12.3, O3: 56.1, USEPAIndex: 2, UV: 6}
+	if aq != want {
+		t.Fatalf("expected %+v, got %+v", want, aq)
+	}
+}
+
+func TestOpenMeteoAirQuality_Errors(t *testing.T) {
+	fakeOpenMeteo(t, "null")
+
+	if _, err := OpenMeteoAirQuality.AirQuality(context.Background(), "Cidade Inexistente"); !errors.Is(err, ErrCityNotFound) {
+		t.Errorf("expected ErrCityNotFound, got %v", err)
+	}
+	if _, err := OpenMeteoAirQuality.AirQuality(context.Background(), "Linhares"); err == nil {
+		t.Errorf("expected error for missing air quality data")
+	}
+}
+
+func TestUSEPAIndex(t *testing.T) {
+	cases := map[float64]int{0: 1, 50: 1, 51: 2, 150: 3, 175: 4, 300: 5, 301: 6, 500: 6}
+	for aqi, want := range cases {
+		if got := usEPAIndex(aqi); got != want {
+			t.Errorf("usEPAIndex(%v) = %d, want %d", aqi, got, want)
+		}
+	}
+}
+
+func TestAirQualityProviders(t *testing.T) {
+	providers, err := AirQualityProviders("open-meteo", "weatherapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers) != 2 || providers[0] != OpenMeteoAirQuality || providers[1] != WeatherAPIAirQuality {
+		t.Fatalf("expected providers in the given order, got %v", providers)
+	}
+	if _, err := AirQualityProviders("weatherapi", "airnow"); err == nil {
+		t.Fatalf("expected error for unknown provider")
+	}
+}
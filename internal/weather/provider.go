@@ -0,0 +1,54 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+)
+
+// AirQualityProvider é uma fonte de dados de qualidade do ar
+//
+// O Serviço B consulta os provedores na ordem configurada e usa o primeiro
+// que responder, de modo que a falha de uma fonte não indisponibiliza o
+// endpoint /air-quality.
+type AirQualityProvider interface {
+	// Name identifica o provedor na configuração e nos spans (ex: "weatherapi")
+	Name() string
+	// AirQuality consulta a qualidade do ar atual para uma cidade
+	AirQuality(ctx context.Context, city string) (AirQuality, error)
+}
+
+// Provedores de qualidade do ar disponíveis
+var (
+	// WeatherAPIAirQuality consulta a WeatherAPI (aqi=yes), usando o pool de chaves
+	WeatherAPIAirQuality AirQualityProvider = weatherAPIAirQuality{}
+	// OpenMeteoAirQuality consulta a API de qualidade do ar da Open-Meteo, que dispensa chave
+	OpenMeteoAirQuality AirQualityProvider = openMeteoAirQuality{}
+)
+
+// airQualityProviders associa cada nome aceito na configuração ao provedor
+var airQualityProviders = map[string]AirQualityProvider{
+	WeatherAPIAirQuality.Name(): WeatherAPIAirQuality,
+	OpenMeteoAirQuality.Name():  OpenMeteoAirQuality,
+}
+
+// AirQualityProviders retorna os provedores com os nomes informados, na mesma ordem
+func AirQualityProviders(names ...string) ([]AirQualityProvider, error) {
+	providers := make([]AirQualityProvider, 0, len(names))
+	for _, name := range names {
+		p, ok := airQualityProviders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown air quality provider %q", name)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// weatherAPIAirQuality implementa AirQualityProvider com GetAirQuality
+type weatherAPIAirQuality struct{}
+
+func (weatherAPIAirQuality) Name() string { return "weatherapi" }
+
+func (weatherAPIAirQuality) AirQuality(ctx context.Context, city string) (AirQuality, error) {
+	return GetAirQuality(ctx, city)
+}
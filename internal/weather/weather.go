@@ -9,14 +9,11 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 
 	// Importação do OpenTelemetry para instrumentação HTTP
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 )
 
 // ApiURL é a URL base da API WeatherAPI para consulta de temperatura
@@ -28,17 +25,56 @@ var ApiURL = "https://api.weatherapi.com/v1/current.json?key=%s&q=%s"
 // Exemplo de resposta:
 // {
 //   "current": {
-//     "temp_c": 25.0,  // Temperatura em Celsius
+//     "temp_c": 25.0,        // Temperatura em Celsius
+//     "feelslike_c": 27.1,   // Sensação térmica em Celsius
+//     "humidity": 78,        // Umidade relativa do ar (%)
+//     "wind_kph": 12.2,      // Velocidade do vento em km/h
+//     "wind_dir": "NE",      // Direção do vento
+//     "uv": 6.0,             // Índice UV
+//     "pressure_mb": 1012.0, // Pressão atmosférica em milibares
+//     "condition": {"text": "Partly cloudy"},
 //     ...
 //   }
 // }
 type WeatherResponse struct {
 	Current struct {
-		TempC float64 `json:"temp_c"` // Temperatura atual em graus Celsius
+		TempC      float64 `json:"temp_c"`      // Temperatura atual em graus Celsius
+		FeelsLikeC float64 `json:"feelslike_c"` // Sensação térmica em graus Celsius
+		Humidity   int     `json:"humidity"`    // Umidade relativa do ar, em porcentagem
+		WindKph    float64 `json:"wind_kph"`    // Velocidade do vento em km/h
+		WindDir    string  `json:"wind_dir"`    // Direção do vento (ex: "NE")
+		UV         float64 `json:"uv"`          // Índice UV
+		PressureMB float64 `json:"pressure_mb"` // Pressão atmosférica em milibares (hPa)
+		Condition  struct {
+			Text string `json:"text"` // Descrição da condição do tempo (ex: "Partly cloudy")
+		} `json:"condition"`
 	} `json:"current"`
 }
 
+// Conditions representa as condições atuais do tempo em uma cidade
+// Todos os valores vêm da mesma chamada à WeatherAPI (current.json)
+type Conditions struct {
+	TempC      float64 // Temperatura em graus Celsius
+	FeelsLikeC float64 // Sensação térmica em graus Celsius
+	Humidity   int     // Umidade relativa do ar, em porcentagem
+	WindKph    float64 // Velocidade do vento em km/h
+	WindDir    string  // Direção do vento (ex: "NE")
+	UV         float64 // Índice UV
+	PressureMB float64 // Pressão atmosférica em milibares (hPa)
+	Text       string  // Descrição da condição do tempo
+}
+
 // GetTemperature consulta a WeatherAPI para obter a temperatura atual em Celsius para uma cidade.
+// Atalho para GetConditions quando apenas a temperatura é necessária.
+func GetTemperature(ctx context.Context, city string) (float64, error) {
+	c, err := GetConditions(ctx, city)
+	if err != nil {
+		return 0, err
+	}
+	return c.TempC, nil
+}
+
+// GetConditions consulta a WeatherAPI para obter as condições atuais do tempo para uma cidade.
 //
 // IMPORTANTE: Esta função implementa rastreamento distribuído com OpenTelemetry:
 // - Cria um span para medir o tempo de resposta da chamada à API WeatherAPI
@@ -50,9 +86,9 @@ type WeatherResponse struct {
 //   - city: Nome da cidade para consultar a temperatura
 //
 // Retorna:
-//   - float64: Temperatura em graus Celsius
+//   - Conditions: Temperatura, sensação térmica, umidade, vento, índice UV e condição do tempo
 //   - error: Erro caso a consulta falhe (API key ausente, falha na requisição, etc.)
-func GetTemperature(ctx context.Context, city string) (float64, error) {
+func GetConditions(ctx context.Context, city string) (Conditions, error) {
 	// Obtém o tracer para criar spans de rastreamento
 	tracer := otel.Tracer("weather-service")
 	
@@ -62,53 +98,34 @@ func GetTemperature(ctx context.Context, city string) (float64, error) {
 	ctx, span := tracer.Start(ctx, "weatherapi-call")
 	defer span.End() // Garante que o span será finalizado mesmo em caso de erro
 	
-	// Obtém a chave da API WeatherAPI das variáveis de ambiente
-	// Esta chave é obrigatória e deve ser configurada antes da execução
-	apiKey := os.Getenv("WEATHER_API_KEY")
-	if apiKey == "" {
-		err := fmt.Errorf("WEATHER_API_KEY not set")
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return 0, err
-	}
-
 	// Codifica o nome da cidade para URL (trata espaços e caracteres especiais)
+	// e monta a URL com a chave escolhida a cada tentativa (ver callWithKey)
 	escapedCity := url.QueryEscape(city)
-	fullURL := fmt.Sprintf(ApiURL, apiKey, escapedCity)
+	urlFor := func(key string) string {
+		return fmt.Sprintf(ApiURL, key, escapedCity)
+	}
 
 	// Adiciona atributos ao span para facilitar análise e debugging
 	// Esses atributos estarão disponíveis no Zipkin para visualização
 	span.SetAttributes(
-		attribute.String("weatherapi.city", city),      // Cidade consultada
-		attribute.String("http.url", fullURL),          // URL da requisição (sem API key por segurança)
+		attribute.String("weatherapi.city", city),           // Cidade consultada
+		attribute.String("http.url", redactURL(urlFor(""))), // URL da requisição (sem API key por segurança)
 	)
 
 	fmt.Println("Consultando WeatherAPI para cidade:", city)
-	fmt.Println("URL:", fullURL)
 
-	// Cria um cliente HTTP instrumentado com OpenTelemetry
-	// O transporte OTEL automaticamente cria spans adicionais para a requisição HTTP
-	// e captura métricas como latência, tamanho da requisição/resposta, etc.
-	client := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
-	}
-	
-	// Cria a requisição HTTP GET com contexto para propagação de traces
-	// O contexto contém o span atual que será propagado através da rede
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
-	if err != nil {
-		span.RecordError(err) // Registra o erro no span
-		span.SetStatus(codes.Error, err.Error())
-		return 0, err
-	}
-	
 	// Executa a requisição HTTP à API WeatherAPI
-	// Esta é a chamada externa cujo tempo de resposta será medido pelo span
-	resp, err := client.Do(req)
+	// Esta é a chamada externa cujo tempo de resposta será medido pelo span.
+	// Cada tentativa aguarda o limitador de taxa (ver SetLimiter) e usa uma
+	// chave do pool (ver SetKeyPool); o cliente é instrumentado com
+	// OpenTelemetry e a chave é removida da URL registrada nos spans (ver
+	// newClient). Respostas 401 e 403 colocam a chave em quarentena e a
+	// consulta é repetida com a próxima chave (ver callWithKey)
+	resp, err := callWithKey(ctx, urlFor)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return 0, err
+		return Conditions{}, err
 	}
 	defer resp.Body.Close() // Garante que o body será fechado
 
@@ -130,7 +147,7 @@ func GetTemperature(ctx context.Context, city string) (float64, error) {
 		err := fmt.Errorf("weather lookup failed")
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return 0, err
+		return Conditions{}, err
 	}
 
 	// Decodifica a resposta JSON da API WeatherAPI
@@ -138,7 +155,7 @@ func GetTemperature(ctx context.Context, city string) (float64, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&weatherResp); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return 0, err
+		return Conditions{}, err
 	}
 	
 	// Adiciona a temperatura obtida ao span para facilitar análise
@@ -148,5 +165,15 @@ func GetTemperature(ctx context.Context, city string) (float64, error) {
 	// Marca o span como bem-sucedido
 	span.SetStatus(codes.Ok, "Temperatura obtida com sucesso")
 
-	return weatherResp.Current.TempC, nil
+	cur := weatherResp.Current
+	return Conditions{
+		TempC:      cur.TempC,
+		FeelsLikeC: cur.FeelsLikeC,
+		Humidity:   cur.Humidity,
+		WindKph:    cur.WindKph,
+		WindDir:    cur.WindDir,
+		UV:         cur.UV,
+		PressureMB: cur.PressureMB,
+		Text:       cur.Condition.Text,
+	}, nil
 }
\ No newline at end of file
@@ -1,14 +1,22 @@
 package weather
 
 import (
+	"cep-weather/internal/ratelimit"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 )
 
+// setTestKey define a chave da WeatherAPI durante o teste
+func setTestKey(t *testing.T, key string) {
+	t.Helper()
+	SetAPIKey(key)
+	t.Cleanup(func() { SetAPIKey("") })
+}
+
 func TestGetTemperature_Success(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -20,9 +28,7 @@ func TestGetTemperature_Success(t *testing.T) {
 	ApiURL = srv.URL + "/?key=%s&q=%s"
 	defer func() { ApiURL = origApiURL }()
 
-	origKey := os.Getenv("WEATHER_API_KEY")
-	os.Setenv("WEATHER_API_KEY", "testkey")
-	defer os.Setenv("WEATHER_API_KEY", origKey)
+	setTestKey(t, "testkey")
 
 	ctx := context.Background()
 	temp, err := GetTemperature(ctx, "Sao Paulo")
@@ -46,9 +52,7 @@ func TestGetTemperature_ApiError(t *testing.T) {
 	ApiURL = srv.URL + "/?key=%s&q=%s"
 	defer func() { ApiURL = origApiURL }()
 
-	origKey := os.Getenv("WEATHER_API_KEY")
-	os.Setenv("WEATHER_API_KEY", "badkey")
-	defer os.Setenv("WEATHER_API_KEY", origKey)
+	setTestKey(t, "badkey")
 
 	ctx := context.Background()
 	_, err := GetTemperature(ctx, "Sao Paulo")
@@ -58,13 +62,9 @@ func TestGetTemperature_ApiError(t *testing.T) {
 }
 
 func TestGetTemperature_NoApiKey(t *testing.T) {
-	origKey := os.Getenv("WEATHER_API_KEY")
-	os.Unsetenv("WEATHER_API_KEY")
-	defer func() {
-		if origKey != "" {
-			os.Setenv("WEATHER_API_KEY", origKey)
-		}
-	}()
+	// Sem chave configurada, a variável de ambiente não é consultada
+	t.Setenv("WEATHER_API_KEY", "from-env")
+	SetAPIKey("")
 
 	origApiURL := ApiURL
 	ApiURL = "https://example.invalid/?key=%s&q=%s"
@@ -76,3 +76,78 @@ func TestGetTemperature_NoApiKey(t *testing.T) {
 		t.Fatalf("expected WEATHER_API_KEY not set error, got %v", err)
 	}
 }
+
+func TestGetConditions_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"current":{"temp_c":21.5,"feelslike_c":23,"humidity":78,"wind_kph":12.2,"wind_dir":"NE","uv":6,"condition":{"text":"Partly cloudy"}}}`)
+	}))
+	defer srv.Close()
+
+	origApiURL := ApiURL
+	ApiURL = srv.URL + "/?key=%s&q=%s"
+	defer func() { ApiURL = origApiURL }()
+	setTestKey(t, "testkey")
+
+	got, err := GetConditions(context.Background(), "Sao Paulo")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := Conditions{TempC: 21.5, FeelsLikeC: 23, Humidity: 78, WindKph: 12.2, WindDir: "NE", UV: 6, Text: "Partly cloudy"}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestSetAPIKey(t *testing.T) {
+	var key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.URL.Query().Get("key")
+		fmt.Fprintln(w, `{"current":{"temp_c":20}}`)
+	}))
+	defer srv.Close()
+
+	origURL := ApiURL
+	ApiURL = srv.URL + "/?key=%s&q=%s"
+	defer func() { ApiURL = origURL }()
+
+	// Apenas a chave configurada é usada, nunca a variável de ambiente
+	t.Setenv("WEATHER_API_KEY", "from-env")
+	setTestKey(t, "configured")
+
+	if _, err := GetTemperature(context.Background(), "Linhares"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if key != "configured" {
+		t.Fatalf("expected configured key, got %q", key)
+	}
+}
+
+func TestGetConditions_RateLimited(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintln(w, `{"current":{"temp_c":20}}`)
+	}))
+	defer srv.Close()
+
+	origURL := ApiURL
+	ApiURL = srv.URL + "/?key=%s&q=%s"
+	defer func() { ApiURL = origURL }()
+	setTestKey(t, "testkey")
+
+	SetLimiter(ratelimit.New("weatherapi", 0.001, 1, ratelimit.FailFast))
+	defer SetLimiter(nil)
+
+	if _, err := GetConditions(context.Background(), "Linhares"); err != nil {
+		t.Fatalf("expected first call to succeed, got %v", err)
+	}
+	_, err := GetConditions(context.Background(), "Linhares")
+	var limited *ratelimit.RateLimited
+	if !errors.As(err, &limited) || limited.Limiter != "weatherapi" {
+		t.Fatalf("expected RateLimited error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected rate limited call not to reach the API, got %d calls", calls)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"cep-weather/internal/auth"
+	"cep-weather/internal/problem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// authenticator exige a credencial do cliente nas requisições aos endpoints de clima
+//
+// A credencial é um token de acesso (JWT) no cabeçalho Authorization: Bearer,
+// validado pelo Verifier, ou uma chave de API procurada no KeyStore. Sem
+// credencial válida, o cliente recebe 401 (Unauthorized) e, quando a
+// credencial não dá acesso à rota, 403 (Forbidden). Com as cotas ativas, as
+// credenciais recusadas contam contra o IP (ver quotaLimiter.allowAttempt).
+// O cliente autenticado
+// segue no contexto (ver auth.FromContext), nos atributos client.* do span e
+// no baggage, que o leva até o Serviço B.
+type authenticator struct {
+	keys   *auth.KeyStore // Chaves de API (nil quando apenas tokens são aceitos)
+	header string         // Cabeçalho com a chave de API (ex: "X-API-Key")
+	tokens *auth.Verifier // Validação dos tokens (nil quando tokens não são aceitos)
+	// attempts limita as credenciais recusadas por IP (nil quando as cotas estão desativadas)
+	attempts *quotaLimiter
+}
+
+// Motivos de recusa das chaves de API
+var (
+	errNoCredentials = errors.New("no valid credentials")
+	errKeyNotAllowed = errors.New("API key does not grant access to the endpoint")
+)
+
+// middleware autentica a requisição antes de repassá-la ao handler da rota
+//
+// Parâmetros:
+//   - route: Rota do endpoint, conforme os endpoints do arquivo de chaves e os escopos (ex: "/weather/{cep}")
+//   - next: Handler do endpoint
+func (a *authenticator) middleware(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		// A resposta depende da credencial: caches não podem reaproveitá-la
+		// para requisições com outra credencial
+		w.Header().Add("Vary", "Authorization")
+		if a.keys != nil {
+			w.Header().Add("Vary", a.header)
+		}
+		if a.attempts != nil && !a.attempts.allowAttempt(w, r) {
+			return
+		}
+		c, err := a.authenticate(r)
+		if err != nil {
+			a.reject(w, r, err)
+			return
+		}
+
+		span.SetAttributes(
+			attribute.String("client.name", c.Name),
+			attribute.String("client.tier", c.Tier),
+			attribute.String("client.key_id", c.KeyID),
+			attribute.String("client.auth_method", c.Method),
+		)
+		if c.Tenant != "" {
+			span.SetAttributes(attribute.String("tenant.id", c.Tenant))
+		}
+		if !c.Allows(route) {
+			reason := errKeyNotAllowed
+			if c.Method == auth.MethodJWT {
+				reason = auth.ErrInsufficientScope
+			}
+			a.reject(w, r, fmt.Errorf("%w: %s", reason, route))
+			return
+		}
+
+		ctx := auth.WithClient(r.Context(), c)
+		ctx = baggage.ContextWithBaggage(ctx, clientBaggage(baggage.FromContext(ctx), c))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticate identifica o cliente pelo token, quando presente, ou pela chave de API
+func (a *authenticator) authenticate(r *http.Request) (auth.Client, error) {
+	if token, ok := bearerToken(r); ok && a.tokens != nil {
+		return a.tokens.Verify(r.Context(), token)
+	}
+	if a.keys != nil {
+		if c, ok := a.keys.Lookup(r.Header.Get(a.header)); ok {
+			return c, nil
+		}
+	}
+	return auth.Client{}, errNoCredentials
+}
+
+// reject responde 401 ou 403 conforme o motivo da recusa
+// Quando tokens são aceitos, a resposta informa o esquema e o erro em
+// WWW-Authenticate (RFC 6750)
+func (a *authenticator) reject(w http.ResponseWriter, r *http.Request, err error) {
+	span := trace.SpanFromContext(r.Context())
+	span.AddEvent("auth.rejected", trace.WithAttributes(attribute.String("auth.error", err.Error())))
+	forbidden := errors.Is(err, errKeyNotAllowed) || errors.Is(err, auth.ErrInsufficientScope)
+	if a.attempts != nil && !forbidden {
+		a.attempts.failedAttempt(r)
+	}
+
+	switch {
+	case errors.Is(err, errKeyNotAllowed):
+		problem.Write(w, r, problem.Forbidden, "the API key does not grant access to this endpoint")
+	case errors.Is(err, auth.ErrInsufficientScope):
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		problem.Write(w, r, problem.Forbidden, "the token scopes do not grant access to this endpoint")
+	case errors.Is(err, errNoCredentials) && a.tokens == nil:
+		problem.Write(w, r, problem.Unauthorized, "a valid API key is required")
+	case errors.Is(err, errNoCredentials):
+		w.Header().Set("WWW-Authenticate", `Bearer realm="cep-weather"`)
+		problem.Write(w, r, problem.Unauthorized, "a valid API key or bearer token is required")
+	default:
+		// Token recusado ou JWKS indisponível: o cliente deve obter novo token
+		w.Header().Set("WWW-Authenticate", `Bearer realm="cep-weather", error="invalid_token"`)
+		problem.Write(w, r, problem.Unauthorized, "the bearer token is invalid or expired")
+	}
+}
+
+// bearerToken extrai o token do cabeçalho Authorization: Bearer <token>
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// clientBaggage inclui a identidade do cliente (tenant.id, client.name e
+// client.tier) no baggage propagado ao Serviço B
+// Valores que não podem ser representados no baggage são omitidos
+func clientBaggage(b baggage.Baggage, c auth.Client) baggage.Baggage {
+	for key, value := range map[string]string{"tenant.id": c.Tenant, "client.name": c.Name, "client.tier": c.Tier} {
+		if value == "" {
+			continue
+		}
+		if m, err := baggage.NewMember(key, value); err == nil {
+			b, _ = b.SetMember(m)
+		}
+	}
+	return b
+}
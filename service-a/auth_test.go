@@ -0,0 +1,235 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/auth"
+	"cep-weather/internal/ratelimit"
+	"cep-weather/internal/telemetry"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// testAuthenticator cria um authenticator com duas chaves: "key-acme", com
+// acesso a todas as rotas, e "key-limited", restrita a GET /weather/{cep}
+func testAuthenticator(t *testing.T) *authenticator {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	content := `
+keys:
+  - {client: acme, key: key-acme, tenant: acme-corp, tier: premium}
+  - {client: limited, key: key-limited, endpoints: ["/weather/{cep}"]}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.NewKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authenticator{keys: keys, header: "X-API-Key"}
+}
+
+func TestAuthenticator(t *testing.T) {
+	a := testAuthenticator(t)
+	var got auth.Client
+	h := a.middleware("/weather", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = auth.FromContext(r.Context())
+	}))
+
+	cases := []struct {
+		name, key string
+		want      int
+	}{
+		{"missing key", "", http.StatusUnauthorized},
+		{"unknown key", "key-unknown", http.StatusUnauthorized},
+		{"endpoint not allowed", "key-limited", http.StatusForbidden},
+		{"valid key", "key-acme", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/weather", nil)
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+	if got.Name != "acme" || got.Tier != "premium" || got.KeyID != auth.KeyID("key-acme") {
+		t.Fatalf("expected authenticated client in context, got %+v", got)
+	}
+}
+
+// TestAuthenticator_Attempts verifica que as credenciais recusadas contam
+// contra o IP, com a cota do plano anônimo
+func TestAuthenticator_Attempts(t *testing.T) {
+	a := testAuthenticator(t)
+	a.attempts = newQuotaLimiter(testLimits(), ratelimit.NewMemoryStore())
+	h := a.middleware("/weather", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(remoteAddr, key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/weather", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Acessos negados (403) não contam; a cota anônima dos testes permite 2 recusas
+	for i, key := range []string{"key-limited", "guess-1", "guess-2"} {
+		want := http.StatusUnauthorized
+		if i == 0 {
+			want = http.StatusForbidden
+		}
+		if got := send("203.0.113.1:1234", key); got != want {
+			t.Fatalf("%s: expected %d, got %d", key, want, got)
+		}
+	}
+	// Esgotadas as tentativas, nem a chave válida é verificada
+	if got := send("203.0.113.1:1234", "key-acme"); got != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after failed attempts, got %d", got)
+	}
+	if got := send("203.0.113.2:1234", "key-acme"); got != http.StatusOK {
+		t.Fatalf("expected other IPs to be unaffected, got %d", got)
+	}
+}
+
+// signES256 emite um token ES256 com as claims informadas
+func signES256(t *testing.T, key *ecdsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	enc := base64.RawURLEncoding.EncodeToString
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signed := enc(header) + "." + enc(payload)
+	digest := sha256.Sum256([]byte(signed))
+	r, sig, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + enc(append(r.FillBytes(make([]byte, 32)), sig.FillBytes(make([]byte, 32))...))
+}
+
+func TestAuthenticator_Bearer(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, 32))) }
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "EC", "kid": "test", "crv": "P-256", "x": enc(key.X), "y": enc(key.Y)},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens e chaves de API são aceitos juntos
+	a := testAuthenticator(t)
+	scopes := map[string][]string{"weather:read": {"/weather/{cep}"}}
+	a.tokens = auth.NewVerifier(auth.NewKeySet(path, time.Minute), "https://idp.example.com", "cep-weather", 0, scopes, "")
+
+	token := func(scope string, exp time.Time) string {
+		return signES256(t, key, map[string]any{
+			"iss": "https://idp.example.com", "aud": "cep-weather", "sub": "reports",
+			"exp": exp.Unix(), "scope": scope,
+		})
+	}
+	valid := token("weather:read", time.Now().Add(time.Hour))
+
+	var got auth.Client
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got, _ = auth.FromContext(r.Context()) })
+	cases := []struct {
+		name, route, authorization, key string
+		want                            int
+		challenge                       string
+	}{
+		{"valid token", "/weather/{cep}", "Bearer " + valid, "", http.StatusOK, ""},
+		{"scope without endpoint", "/weather/batch", "Bearer " + valid, "", http.StatusForbidden, `Bearer error="insufficient_scope"`},
+		{"unknown scope", "/weather/{cep}", "Bearer " + token("profile", time.Now().Add(time.Hour)), "", http.StatusForbidden, `Bearer error="insufficient_scope"`},
+		{"expired token", "/weather/{cep}", "Bearer " + token("weather:read", time.Now().Add(-time.Hour)), "", http.StatusUnauthorized, `Bearer realm="cep-weather", error="invalid_token"`},
+		{"invalid token despite valid key", "/weather/{cep}", "Bearer garbage", "key-acme", http.StatusUnauthorized, `Bearer realm="cep-weather", error="invalid_token"`},
+		{"api key", "/weather/batch", "", "key-acme", http.StatusOK, ""},
+		{"no credentials", "/weather/{cep}", "", "", http.StatusUnauthorized, `Bearer realm="cep-weather"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/weather/01310100", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			rec := httptest.NewRecorder()
+			a.middleware(tc.route, next).ServeHTTP(rec, req)
+			if rec.Code != tc.want || rec.Header().Get("WWW-Authenticate") != tc.challenge {
+				t.Fatalf("expected %d %q, got %d %q: %s", tc.want, tc.challenge, rec.Code, rec.Header().Get("WWW-Authenticate"), rec.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/weather/01310100", nil)
+	req.Header.Set("Authorization", "Bearer "+valid)
+	a.middleware("/weather/{cep}", next).ServeHTTP(httptest.NewRecorder(), req)
+	if got.Name != "reports" || got.Method != auth.MethodJWT {
+		t.Fatalf("expected token client in context, got %+v", got)
+	}
+}
+
+// TestAuthenticator_Baggage verifica que a identidade do cliente chega ao
+// Serviço B no baggage, mesmo que o cliente tente informar outra
+func TestAuthenticator_Baggage(t *testing.T) {
+	setupTracer(t)
+	orig := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(telemetry.Propagator())
+	t.Cleanup(func() { otel.SetTextMapPropagator(orig) })
+
+	var members map[string]string
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		members = map[string]string{}
+		for _, m := range baggage.FromContext(ctx).Members() {
+			members[m.Key()] = m.Value()
+		}
+		json.NewEncoder(w).Encode(api.WeatherResponse{City: "São Paulo"})
+	}))
+	defer serviceB.Close()
+
+	p := newProxy(serviceB.URL, time.Second, time.Second)
+	h := routeHandler("/weather", testAuthenticator(t).middleware("/weather", newPostHandler(p)))
+	req := httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(`{"cep": "01310100"}`))
+	req.Header.Set("X-API-Key", "key-acme")
+	req.Header.Set("baggage", "client.name=intruder")
+	req.Header.Set("X-Request-ID", "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	want := map[string]string{"tenant.id": "acme-corp", "client.name": "acme", "client.tier": "premium", "request.id": "req-42"}
+	if !maps.Equal(members, want) {
+		t.Fatalf("expected %v in baggage, got %v", want, members)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/problem"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newBatchHandler cria o handler do endpoint POST /weather/batch
+//
+// Recebe uma lista de CEPs ({"ceps": ["29902555", "01310-100"]}) e devolve um
+// resultado por CEP, na mesma ordem. Cada resultado contém o clima ou o erro
+// (RFC 7807) daquele CEP; falhas individuais não afetam os demais itens.
+// A validação e a consulta de cada CEP são feitas pelo Serviço B.
+//
+// Clientes que aceitam application/x-ndjson ou text/event-stream recebem os
+// resultados em streaming, conforme cada CEP é resolvido (ver streamBatch),
+// com no máximo "streamConcurrency" consultas em paralelo. Com as cotas
+// ativas, o lote custa uma unidade da cota por CEP (ver chargeQuota).
+func newBatchHandler(p *proxy, streamConcurrency int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported")
+			return
+		}
+
+		f, ok := bestMatch(r.Header.Get("Accept"), batchFormats, batchJSON)
+		if !ok {
+			problem.Write(w, r, problem.NotAcceptable, "supported media types: application/json, application/x-ndjson, text/event-stream")
+			return
+		}
+
+		var req api.BatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.InvalidBatch, "request body must be a JSON object with a ceps array")
+			return
+		}
+		// Rejeita lotes vazios ou acima do limite antes de acionar o Serviço B
+		if err := req.Validate(); err != nil {
+			problem.Write(w, r, problem.InvalidBatch, err.Error())
+			return
+		}
+		// Cada CEP é uma consulta ao Serviço B: o lote custa uma unidade da
+		// cota por CEP (a requisição já cobrou a primeira)
+		if !chargeQuota(w, r, len(req.CEPs)-1) {
+			return
+		}
+
+		if f.streaming() {
+			streamBatch(p, w, r, req, f, streamConcurrency)
+			return
+		}
+
+		tracer := otel.Tracer("service-a")
+		ctx, span := tracer.Start(r.Context(), "process-batch")
+		defer span.End()
+		span.SetAttributes(attribute.Int("batch.size", len(req.CEPs)))
+
+		resp, ok := p.forwardBatch(ctx, w, r, req)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			span.RecordError(err)
+			log.Printf("Erro ao enviar a resposta ao cliente: %v", err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/i18n"
+	"cep-weather/internal/problem"
+	"cep-weather/internal/telemetry"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newGRPCProxy cria o proxy para o Serviço B usando a API gRPC (WeatherService)
+// A conexão é estabelecida sob demanda, na primeira chamada
+//
+// Parâmetros:
+//   - target: Endereço gRPC do Serviço B (ex: "service-b:50051")
+//   - timeout: Tempo máximo de espera por uma resposta individual (0 desativa o limite)
+//   - batchTimeout: Tempo máximo de espera por uma resposta em lote (0 desativa o limite)
+//   - tlsConfig: Configuração TLS da conexão (ver certs.Reloader.ClientConfig); nil conecta sem TLS
+func newGRPCProxy(target string, timeout, batchTimeout time.Duration, tlsConfig *tls.Config) (*proxy, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	// O stats handler OTEL cria spans para cada chamada e propaga o contexto
+	// de rastreamento nos metadados gRPC (W3C Trace Context)
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithPropagators(telemetry.Propagator()),
+		)),
+		grpc.WithUnaryInterceptor(peerInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &proxy{
+		url:          target,
+		timeout:      timeout,
+		batchTimeout: batchTimeout,
+		grpc:         weatherpb.NewWeatherServiceClient(conn),
+	}, nil
+}
+
+// grpcWeather consulta o clima de um CEP pela API gRPC do Serviço B
+func (p *proxy) grpcWeather(ctx context.Context, req api.WeatherRequest) (api.WeatherResponse, *upstreamError) {
+	var resp api.WeatherResponse
+	uerr := p.invoke(ctx, p.timeout, problem.InvalidZipcode, func(ctx context.Context) (err error) {
+		m, err := p.grpc.GetWeather(ctx, &weatherpb.GetWeatherRequest{Cep: req.CEP, Extended: req.Extended})
+		if err != nil {
+			return err
+		}
+		resp, err = api.WeatherFromProto(m)
+		return err
+	})
+	return resp, uerr
+}
+
+// grpcBatch consulta o clima de um lote de CEPs pela API gRPC do Serviço B
+func (p *proxy) grpcBatch(ctx context.Context, req api.BatchRequest) (api.BatchResponse, *upstreamError) {
+	var resp api.BatchResponse
+	uerr := p.invoke(ctx, p.batchTimeout, problem.InvalidBatch, func(ctx context.Context) (err error) {
+		m, err := p.grpc.BatchGetWeather(ctx, &weatherpb.BatchGetWeatherRequest{Ceps: req.CEPs})
+		if err != nil {
+			return err
+		}
+		resp, err = api.BatchResponseFromProto(m, len(req.CEPs))
+		return err
+	})
+	return resp, uerr
+}
+
+// invoke executa uma chamada gRPC ao Serviço B e classifica o resultado
+//
+// Equivalente a do para o transporte gRPC: o código de status é convertido
+// na mesma classificação (outcome) usada nas chamadas HTTP e registrado no
+// span atual. Respostas fora do contrato (api.ErrInvalidResponse) resultam
+// em outcomeInvalid; INVALID_ARGUMENT assume a categoria invalid informada.
+//
+// Retorna nil em caso de sucesso
+func (p *proxy) invoke(ctx context.Context, timeout time.Duration, invalid problem.Kind, call func(context.Context) error) *upstreamError {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("serviceb.protocol", "grpc"),
+		attribute.String("serviceb.url", p.url),
+	)
+
+	fail := func(o outcome, err error, detail string) *upstreamError {
+		span.SetAttributes(attribute.String("serviceb.outcome", string(o)))
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return &upstreamError{outcome: o, detail: detail, err: err}
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Repassa o idioma negociado com o cliente, para que as mensagens dos
+	// itens de um lote venham traduzidas do Serviço B
+	ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", string(i18n.FromContext(ctx)))
+
+	err := call(ctx)
+	if errors.Is(err, api.ErrInvalidResponse) {
+		span.SetAttributes(attribute.String("serviceb.grpc_code", codes.OK.String()))
+		return fail(outcomeInvalid, err, "service-b returned an invalid weather response")
+	}
+
+	st := status.Convert(err)
+	o := classifyCode(st.Code())
+	span.SetAttributes(attribute.String("serviceb.grpc_code", st.Code().String()))
+
+	switch o {
+	case outcomeOK:
+		span.SetAttributes(attribute.String("serviceb.outcome", string(o)))
+		span.SetStatus(otelcodes.Ok, "")
+		return nil
+	case outcomeClientError:
+		// Assim como os 4xx no HTTP, erros do cliente não marcam o span como erro
+		span.SetAttributes(attribute.String("serviceb.outcome", string(o)))
+		kind := invalid
+		if st.Code() == codes.NotFound {
+			kind = problem.ZipcodeNotFound
+		}
+		return &upstreamError{
+			outcome: o,
+			detail:  st.Message(),
+			err:     fmt.Errorf("service-b responded with code %s", st.Code()),
+			status:  kind.Status,
+			kind:    kind,
+		}
+	case outcomeServerError:
+		err := fmt.Errorf("service-b responded with code %s", st.Code())
+		return fail(o, err, err.Error())
+	default:
+		return fail(o, err, fmt.Sprintf("service-b request failed: %s", o))
+	}
+}
+
+// classifyCode classifica o código de status de uma chamada gRPC ao Serviço B
+// - INVALID_ARGUMENT e NOT_FOUND equivalem aos 4xx do HTTP
+// - UNAVAILABLE equivale à conexão recusada (serviço fora do ar)
+// - DEADLINE_EXCEEDED e CANCELED equivalem ao timeout e ao cancelamento
+// - Demais códigos equivalem aos 5xx do HTTP
+func classifyCode(code codes.Code) outcome {
+	switch code {
+	case codes.OK:
+		return outcomeOK
+	case codes.InvalidArgument, codes.NotFound:
+		return outcomeClientError
+	case codes.Unavailable:
+		return outcomeRefused
+	case codes.DeadlineExceeded:
+		return outcomeTimeout
+	case codes.Canceled:
+		return outcomeCanceled
+	default:
+		return outcomeServerError
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"cep-weather/internal/api/weatherpb"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWeatherService simula a API gRPC do Serviço B
+// Registra o trace recebido em cada chamada para verificar a propagação
+type fakeWeatherService struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	traceID chan trace.TraceID
+}
+
+func (f *fakeWeatherService) GetWeather(ctx context.Context, req *weatherpb.GetWeatherRequest) (*weatherpb.Weather, error) {
+	select {
+	case f.traceID <- trace.SpanContextFromContext(ctx).TraceID():
+	default:
+	}
+	switch req.GetCep() {
+	case "29902555":
+		m := &weatherpb.Weather{City: "Linhares", TempC: 28.5, TempF: 83.3, TempK: 301.5}
+		if req.GetExtended() {
+			m.Conditions = &weatherpb.Conditions{
+				FeelsLike: &weatherpb.Temperature{TempC: 31, TempF: 87.8, TempK: 304.15},
+				Humidity:  78,
+				Wind:      &weatherpb.Wind{Kph: 36, MS: 10, Mph: 22.37, Direction: "NE"},
+				Pressure:  &weatherpb.Pressure{Hpa: 1013.25, InHg: 29.92},
+			}
+		}
+		return m, nil
+	case "29902556":
+		// Condições sem o vento: resposta inválida
+		return &weatherpb.Weather{City: "Linhares", TempC: 28.5, TempF: 83.3, TempK: 301.5, Conditions: &weatherpb.Conditions{
+			FeelsLike: &weatherpb.Temperature{},
+			Pressure:  &weatherpb.Pressure{},
+		}}, nil
+	case "99999999":
+		return &weatherpb.Weather{City: "", TempC: 1, TempF: 1, TempK: 1}, nil
+	default:
+		return nil, status.Error(grpccodes.NotFound, "Zipcode not found")
+	}
+}
+
+// newGRPCServiceB inicia o WeatherService simulado em uma porta local
+func newGRPCServiceB(t *testing.T) (string, *fakeWeatherService) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeWeatherService{traceID: make(chan trace.TraceID, 1)}
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithPropagators(propagation.TraceContext{}),
+	)))
+	weatherpb.RegisterWeatherServiceServer(s, fake)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String(), fake
+}
+
+func TestGRPCProxy_Success(t *testing.T) {
+	sr := setupTracer(t)
+	addr, fake := newGRPCServiceB(t)
+	p, err := newGRPCProxy(addr, time.Second, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := doRequest(p, `{"cep":"29902-555"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"city":"Linhares"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+	span := processSpan(t, sr)
+	if span.Status().Code != codes.Ok || outcomeOf(span) != "ok" {
+		t.Fatalf("expected ok span, got %v / %q", span.Status(), outcomeOf(span))
+	}
+	// O Serviço B deve continuar o trace iniciado no Serviço A
+	if got := <-fake.traceID; got != span.SpanContext().TraceID() {
+		t.Fatalf("expected trace %s to reach service-b, got %s", span.SpanContext().TraceID(), got)
+	}
+}
+
+func TestGRPCProxy_Extended(t *testing.T) {
+	addr, _ := newGRPCServiceB(t)
+	p, err := newGRPCProxy(addr, time.Second, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := doRequest(p, `{"cep":"29902555"}`)
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), `"conditions"`) {
+		t.Fatalf("expected response without conditions, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// O campo extended é repassado ao Serviço B, tanto no POST quanto no GET
+	rec = doRequest(p, `{"cep":"29902555","extended":true}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"humidity":78`) {
+		t.Fatalf("expected extended response, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	newGetHandler(p).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/weather/29902555?extended=true", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"direction":"NE"`) {
+		t.Fatalf("expected extended response, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGRPCProxy_Errors(t *testing.T) {
+	addr, _ := newGRPCServiceB(t)
+	p, err := newGRPCProxy(addr, time.Second, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		cep    string
+		status int
+		code   string
+	}{
+		"not found":          {"01310100", http.StatusNotFound, "zipcode_not_found"},
+		"invalid response":   {"99999999", http.StatusBadGateway, "bad_gateway"},
+		"invalid conditions": {"29902556", http.StatusBadGateway, "bad_gateway"},
+	}
+	for name, tc := range cases {
+		rec := doRequest(p, `{"cep":"`+tc.cep+`"}`)
+		if rec.Code != tc.status || !strings.Contains(rec.Body.String(), `"code":"`+tc.code+`"`) {
+			t.Errorf("%s: expected %d %s, got %d: %s", name, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestGRPCProxy_Unavailable(t *testing.T) {
+	// Reserva uma porta e a libera, garantindo que não há servidor escutando
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	p, err := newGRPCProxy(addr, time.Second, time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := doRequest(p, `{"cep":"29902555"}`)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
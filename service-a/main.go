@@ -5,122 +5,165 @@ package main
 
 // Importação das dependências necessárias
 import (
-	"bytes"
+	"cep-weather/internal/api"
+	"cep-weather/internal/auth"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/certs"
+	"cep-weather/internal/config"
+	"cep-weather/internal/i18n"
+	"cep-weather/internal/problem"
+	"cep-weather/internal/ratelimit"
 	"cep-weather/internal/telemetry"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
+	"log"
 	"net/http"
 	"os"
-	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	// Importações para OpenTelemetry - usado para rastreamento distribuído
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// Request define a estrutura do payload JSON recebido do cliente
-// Requisito: deve receber um objeto JSON com campo "cep" contendo 8 dígitos
-type Request struct {
-	CEP string `json:"cep"` // Campo CEP que será recebido no JSON (deve ser string com 8 dígitos)
+// newPostHandler cria o handler do endpoint POST /weather
+// Recebe o CEP no corpo JSON: {"cep": "29902555"}, com o campo opcional
+// "extended" para incluir as condições detalhadas
+func newPostHandler(p *proxy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Validação: Verifica se o método HTTP é POST (conforme requisito)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported; use GET /weather/{cep}")
+			return
+		}
+
+		// Decodifica o JSON do corpo da requisição
+		// Se falhar na decodificação, retorna erro 422 conforme especificação
+		// Requisito: deve receber um objeto JSON com campo "cep" contendo 8 dígitos
+		var req api.WeatherRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.InvalidZipcode, "request body must be a JSON object with a cep field") // 422 conforme requisito
+			return
+		}
+
+		lookup(p, w, r, req.CEP, req.Extended)
+	}
 }
 
-// handler é a função que processa as requisições HTTP recebidas
-// Esta função implementa a lógica principal do Serviço A:
-// 1. Valida se é requisição POST
-// 2. Valida formato do CEP
-// 3. Cria spans de rastreamento
-// 4. Encaminha requisição ao Serviço B
-// 5. Repassa a resposta ao cliente
-func handler(w http.ResponseWriter, r *http.Request) {
-	// Validação: Verifica se o método HTTP é POST (conforme requisito)
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// newGetHandler cria o handler do endpoint GET /weather/{cep}
+// Permite consultar o clima diretamente pela URL, com respostas cacheáveis
+// O parâmetro opcional ?extended=true inclui as condições detalhadas
+func newGetHandler(p *proxy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			problem.Write(w, r, problem.MethodNotAllowed, "only GET is supported; use POST /weather")
+			return
+		}
+
+		// O CEP é o último segmento do caminho: /weather/{cep}
+		rawCEP := strings.TrimPrefix(r.URL.Path, "/weather/")
+		if strings.Contains(rawCEP, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		// Valores inválidos de extended são tratados como false
+		extended, _ := strconv.ParseBool(r.URL.Query().Get("extended"))
+		lookup(p, w, r, rawCEP, extended)
 	}
+}
 
-	// Decodifica o JSON do corpo da requisição
-	// Se falhar na decodificação, retorna erro 422 conforme especificação
-	var req Request
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid zipcode", http.StatusUnprocessableEntity) // 422 conforme requisito
+// lookup implementa a lógica principal do Serviço A, comum às duas rotas:
+// 1. Negocia o formato da resposta (Accept)
+// 2. Valida formato do CEP
+// 3. Cria spans de rastreamento
+// 4. Encaminha requisição ao Serviço B (via proxy)
+// 5. Envia a resposta ao cliente no formato negociado
+//
+// Com extended, o Serviço B inclui as condições detalhadas na resposta
+func lookup(p *proxy, w http.ResponseWriter, r *http.Request, rawCEP string, extended bool) {
+	// Negocia o formato antes de consultar o Serviço B, evitando chamadas
+	// desnecessárias quando nenhum formato aceito pelo cliente é suportado
+	f, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		problem.Write(w, r, problem.NotAcceptable, "supported media types: application/json, application/xml, text/plain")
 		return
 	}
 
-	// Validação: Verifica se o CEP contém exatamente 8 dígitos numéricos (string)
+	// Validação: Interpreta e normaliza o CEP ("01310-100" -> "01310100")
 	// Requisito: CEP deve ser uma string válida com 8 dígitos
-	if !regexp.MustCompile(`^\d{8}$`).MatchString(req.CEP) {
-		http.Error(w, "invalid zipcode", http.StatusUnprocessableEntity) // 422 conforme requisito
+	c, err := cep.Parse(rawCEP)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidZipcode, err.Error()) // 422 conforme requisito
 		return
 	}
 
 	// Cria um span para rastreamento distribuído com OpenTelemetry
 	// Este span será propagado para o Serviço B através do contexto HTTP
 	tracer := otel.Tracer("service-a")
-	ctx := r.Context()
-	ctx, span := tracer.Start(ctx, "process-zipcode")
+	ctx, span := tracer.Start(r.Context(), "process-zipcode")
 	defer span.End() // Garante que o span será finalizado mesmo em caso de erro
 
-	// Obtém a URL do Serviço B das variáveis de ambiente
-	// Permite configurar a URL dinamicamente (útil para Docker/containers)
-	serviceBURL := os.Getenv("SERVICE_B_URL")
-	if serviceBURL == "" {
-		serviceBURL = "http://localhost:8081/weather" // Valor padrão para desenvolvimento local
-	}
+	// UF e região são inferidas localmente a partir da tabela de faixas de CEP
+	span.SetAttributes(
+		attribute.String("cep.uf", c.State()),
+		attribute.String("cep.region", string(c.Region())),
+		attribute.String("response.format", f.mediaType),
+	)
 
-	// Converte a requisição para JSON para enviar ao Serviço B
-	// O Serviço B também espera receber JSON no formato {"cep": "29902555"}
-	jsonBody, err := json.Marshal(req)
-	if err != nil {
-		span.RecordError(err) // Registra o erro no span para rastreamento
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	// Encaminha ao Serviço B; o proxy classifica o resultado da chamada,
+	// registra atributos e status no span atual e responde em caso de erro
+	// O Serviço B recebe sempre o CEP normalizado: {"cep": "29902555"}
+	resp, ok := p.forward(ctx, w, r, api.WeatherRequest{CEP: c.String(), Extended: extended})
+	if !ok {
 		return
 	}
 
-	// Cria um cliente HTTP instrumentado com OpenTelemetry
-	// O transporte OTEL automaticamente cria spans para requisições HTTP
-	// e propaga o contexto de rastreamento distribuído
-	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
-	
-	// Cria a requisição HTTP POST com contexto para propagação de traces
-	// O contexto contém informações de rastreamento que serão propagadas ao Serviço B
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", serviceBURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
+	if err := writeWeather(w, r, f, resp); err != nil {
 		span.RecordError(err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// Envia a requisição para o Serviço B
-	// Esta chamada será automaticamente rastreada pelo OpenTelemetry
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		log.Printf("Erro ao enviar a resposta ao cliente: %v", err)
 	}
-	defer resp.Body.Close() // Garante que o body será fechado
-
-	// Repassa o código de status e cabeçalhos da resposta do Serviço B
-	// O Serviço A funciona como um proxy, repassando a resposta ao cliente
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.StatusCode)
+}
 
-	// Copia o corpo da resposta do Serviço B para o cliente
-	// Esta é uma operação eficiente que evita carregar todo o body na memória
-	if _, err := io.Copy(w, resp.Body); err != nil {
-		span.RecordError(err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+// routeHandler instrumenta o handler com OpenTelemetry usando a rota como nome do span
+// Ex: "GET /weather/{cep}" em vez do caminho concreto, evitando um nome por CEP
+// O Serviço A é a borda do sistema: do cliente, aceita apenas o trace
+// (traceparent ou B3, conforme os propagadores); o baggage recebido é
+// descartado, para que o cliente não informe uma identidade ao Serviço B
+// O baggage é montado aqui, com o identificador da requisição (ver requestID)
+// e a identidade do cliente autenticado (ver authenticator)
+func routeHandler(route string, h http.Handler) http.Handler {
+	return otelhttp.NewHandler(
+		otelhttp.WithRouteTag(route, requestID(h)),
+		route,
+		otelhttp.WithSpanNameFormatter(func(route string, r *http.Request) string {
+			return r.Method + " " + route
+		}),
+		otelhttp.WithPropagators(telemetry.TracePropagator()),
+	)
 }
 
 // função principal - ponto de entrada da aplicação
 func main() {
+	// Carrega a configuração: valores padrão, arquivo opcional (CONFIG_FILE) e
+	// variáveis de ambiente. Valores inválidos interrompem a inicialização
+	cfg := config.DefaultServiceA()
+	if err := config.Load(&cfg, os.Getenv(config.FileEnv)); err != nil {
+		fmt.Printf("Configuração inválida:\n%v\n", err)
+		os.Exit(1)
+	}
+	log.Printf("Configuração efetiva:\n%s", config.Format(cfg))
+
 	// Inicializa o OpenTelemetry com o nome do serviço
 	// Isso configura o sistema de rastreamento distribuído e conexão com Zipkin
-	tp, err := telemetry.InitTracer("service-a")
+	tp, err := telemetry.InitTracer("service-a", cfg.ZipkinURL)
 	if err != nil {
 		fmt.Printf("Erro ao inicializar o tracer: %v\n", err)
 		os.Exit(1)
@@ -133,21 +176,142 @@ func main() {
 		}
 	}()
 
-	// Configura a porta do servidor HTTP
-	// Permite configurar via variável de ambiente (útil para Docker)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" // Porta padrão para o Serviço A conforme requisitos
+	// Métricas do OpenTelemetry (ex: métricas HTTP do otelhttp), expostas
+	// em GET /metrics no formato do Prometheus, na porta metrics.port
+	mp, metricsHandler, err := telemetry.InitMeter("service-a")
+	if err != nil {
+		fmt.Printf("Erro ao inicializar as métricas: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := mp.Shutdown(context.Background()); err != nil {
+			fmt.Printf("Erro ao desligar o provedor de métricas: %v\n", err)
+		}
+	}()
+	if cfg.Metrics.Enabled {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metricsHandler)
+			log.Printf("Métricas disponíveis em :%s/metrics", cfg.Metrics.Port)
+			if err := http.ListenAndServe(":"+cfg.Metrics.Port, mux); err != nil {
+				log.Printf("Erro ao iniciar o servidor de métricas: %v", err)
+			}
+		}()
+	}
+
+	// Define o formato das respostas de erro
+	// legacy_errors (LEGACY_ERRORS=true) mantém as mensagens em texto puro do contrato original
+	problem.Legacy = cfg.LegacyErrors
+
+	// Proporção dos traces amostrados; o Serviço B segue a decisão tomada aqui
+	telemetry.SetSampleRatio(cfg.SamplerRatio)
+
+	// Formatos do contexto propagado (propagators, OTEL_PROPAGATORS); o baggage
+	// leva ao Serviço B a organização, o cliente e o identificador da requisição
+	if err := telemetry.SetPropagators(cfg.Propagators...); err != nil {
+		fmt.Printf("Erro ao configurar os propagadores: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Prazos de espera pela resposta do Serviço B; ao serem excedidos, o
+	// cliente recebe 504 (Gateway Timeout). Lotes grandes levam mais tempo,
+	// por isso o prazo é separado
+	upstream := cfg.ServiceB
+	timeout, batchTimeout := time.Duration(upstream.Timeout), time.Duration(upstream.BatchTimeout)
+
+	// TLS nas chamadas ao Serviço B (service_b.tls); com cert_file, o Serviço A
+	// apresenta o próprio certificado (mTLS). Certificados renovados valem para
+	// as novas conexões sem reinício. O certificado do Serviço B deve conter
+	// server_name ou, na falta dele, o host do endereço usado
+	var reloader *certs.Reloader
+	if upstream.TLS.Enabled {
+		if reloader, err = certs.NewReloader(upstream.TLS.CertFile, upstream.TLS.KeyFile, upstream.TLS.CAFile); err != nil {
+			fmt.Printf("Erro ao carregar os certificados TLS: %v\n", err)
+			os.Exit(1)
+		}
+		if interval := time.Duration(upstream.TLS.ReloadInterval); interval > 0 {
+			reloader.Watch(context.Background(), interval)
+		}
+	}
+
+	p := newProxy(upstream.URL, timeout, batchTimeout)
+	if reloader != nil {
+		p.useTLS(reloader.ClientConfig(serverName(upstream.TLS.ServerName, upstream.URL)))
+	}
+
+	// Protocolo usado na comunicação com o Serviço B: "http" (padrão) ou "grpc"
+	// No modo gRPC, o endereço do WeatherService é service_b.grpc_addr (SERVICE_B_GRPC_ADDR)
+	if upstream.Protocol == "grpc" {
+		var grpcTLS *tls.Config
+		if reloader != nil {
+			grpcTLS = reloader.ClientConfig(serverName(upstream.TLS.ServerName, upstream.GRPCAddr))
+		}
+		if p, err = newGRPCProxy(upstream.GRPCAddr, timeout, batchTimeout, grpcTLS); err != nil {
+			fmt.Printf("Erro ao configurar o cliente gRPC: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Autenticação (auth) e cotas de requisições por cliente (rate_limit)
+	// A chave de API ou o token de acesso identifica o cliente, que só acessa
+	// as rotas permitidas no arquivo de chaves ou pelos escopos do token; as
+	// cotas contam as requisições pela credencial ou pelo IP, com a cota do
+	// plano do cliente, em contadores em memória
+	var chain []func(route string, h http.Handler) http.Handler
+	var limiter *quotaLimiter
+	if cfg.RateLimit.Enabled {
+		limiter = newQuotaLimiter(cfg.RateLimit, ratelimit.NewMemoryStore())
+	}
+	if cfg.Auth.Enabled {
+		// As credenciais recusadas contam contra o IP, limitando tentativas
+		// de adivinhar chaves e tokens
+		a := &authenticator{header: cfg.APIKeyHeader, attempts: limiter}
+		if cfg.Auth.KeysFile != "" {
+			keys, err := auth.NewKeyStore(cfg.Auth.KeysFile)
+			if err != nil {
+				fmt.Printf("Erro ao carregar as chaves de API: %v\n", err)
+				os.Exit(1)
+			}
+			log.Printf("Chaves de API carregadas: %d chaves", keys.Len())
+			if interval := time.Duration(cfg.Auth.ReloadInterval); interval > 0 {
+				go keys.Watch(context.Background(), interval)
+			}
+			a.keys = keys
+		}
+		if jwt := cfg.Auth.JWT; jwt.Enabled {
+			source := jwt.JWKSURL
+			if jwt.JWKSFile != "" {
+				source = jwt.JWKSFile
+			}
+			jwks := auth.NewKeySet(source, time.Duration(jwt.CacheTTL))
+			if err := jwks.Load(context.Background()); err != nil {
+				fmt.Printf("Erro ao carregar as chaves do provedor de identidade: %v\n", err)
+				os.Exit(1)
+			}
+			a.tokens = auth.NewVerifier(jwks, jwt.Issuer, jwt.Audience, time.Duration(jwt.Leeway), jwt.Scopes, jwt.Tier)
+		}
+		chain = append(chain, a.middleware)
+	}
+	if limiter != nil {
+		chain = append(chain, func(_ string, h http.Handler) http.Handler { return limiter.middleware(h) })
+	}
+	protect := func(route string, h http.Handler) http.Handler {
+		for i := len(chain) - 1; i >= 0; i-- {
+			h = chain[i](route, h)
+		}
+		return h
 	}
 
-	// Configura o handler HTTP com instrumentação OpenTelemetry
-	// O otelhttp.NewHandler automaticamente cria spans para cada requisição
-	handler := otelhttp.NewHandler(http.HandlerFunc(handler), "weather-handler")
-	http.Handle("/weather", handler) // Endpoint: POST /weather
+	// Configura os handlers HTTP com instrumentação OpenTelemetry
+	// O otelhttp.NewHandler automaticamente cria spans para cada requisição,
+	// nomeados pela rota (ex: "POST /weather", "GET /weather/{cep}")
+	http.Handle("/weather", routeHandler("/weather", i18n.Middleware(protect("/weather", newPostHandler(p)))))                                           // Endpoint: POST /weather
+	http.Handle("/weather/", routeHandler("/weather/{cep}", i18n.Middleware(protect("/weather/{cep}", newGetHandler(p)))))                               // Endpoint: GET /weather/{cep}
+	http.Handle("/weather/batch", routeHandler("/weather/batch", i18n.Middleware(protect("/weather/batch", newBatchHandler(p, cfg.StreamConcurrency))))) // Endpoint: POST /weather/batch
 
 	// Inicia o servidor HTTP na porta configurada
-	fmt.Printf("Serviço A rodando na porta %s...\n", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	fmt.Printf("Serviço A rodando na porta %s...\n", cfg.Port)
+	if err := http.ListenAndServe(":"+cfg.Port, nil); err != nil {
 		fmt.Printf("Erro ao iniciar o servidor: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"cep-weather/internal/api"
+	"cep-weather/internal/auth"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheMaxAge define por quanto tempo respostas de GET podem ser reaproveitadas
+// por caches (CDN, navegador). A WeatherAPI atualiza as condições atuais a
+// cada 15 minutos, então 5 minutos mantém os dados razoavelmente frescos
+const cacheMaxAge = 5 * time.Minute
+
+// format representa um formato de resposta suportado pelo Serviço A
+type format struct {
+	mediaType   string                                    // Tipo de mídia anunciado ao cliente
+	contentType string                                    // Valor do cabeçalho Content-Type
+	encode      func(api.WeatherResponse) ([]byte, error) // Serializa a resposta no formato
+}
+
+// Formatos suportados pelo Serviço A; JSON é o padrão quando o cliente aceita qualquer um
+var (
+	formatJSON = format{"application/json", "application/json", encodeJSON}
+	formatXML  = format{"application/xml", "application/xml; charset=utf-8", encodeXML}
+	formatText = format{"text/plain", "text/plain; charset=utf-8", encodeText}
+)
+
+// formats mapeia os tipos de mídia aceitos no cabeçalho Accept para os formatos
+var formats = map[string]format{
+	"application/json": formatJSON,
+	"application/xml":  formatXML,
+	"text/xml":         formatXML,
+	"text/plain":       formatText,
+	"application/*":    formatJSON,
+	"text/*":           formatText,
+	"*/*":              formatJSON,
+}
+
+// negotiate escolhe o formato de resposta de clima a partir do cabeçalho Accept
+//
+// Retorna:
+//   - format: Formato escolhido
+//   - bool: false quando nenhum dos tipos aceitos pelo cliente é suportado
+func negotiate(accept string) (format, bool) {
+	return bestMatch(accept, formats, formatJSON)
+}
+
+// bestMatch escolhe, entre os tipos de mídia suportados, o preferido pelo cliente
+//
+// Considera os valores de qualidade (q) de cada tipo de mídia; em caso de
+// empate, vence o que aparece primeiro no cabeçalho. Um cabeçalho ausente
+// equivale a */*, resultando no valor padrão informado.
+func bestMatch[T any](accept string, supported map[string]T, def T) (T, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return def, true
+	}
+
+	var (
+		best  T
+		bestQ float64
+		found bool
+	)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		v, ok := supported[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 && q > bestQ {
+			best, bestQ, found = v, q, true
+		}
+	}
+	return best, found
+}
+
+// encodeJSON serializa a resposta como JSON (formato original do contrato)
+func encodeJSON(resp api.WeatherResponse) ([]byte, error) {
+	return json.Marshal(resp)
+}
+
+// encodeXML serializa a resposta como XML, com <weather> como elemento raiz
+func encodeXML(resp api.WeatherResponse) ([]byte, error) {
+	doc := struct {
+		XMLName xml.Name `xml:"weather"`
+		api.WeatherResponse
+	}{WeatherResponse: resp}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeText serializa a resposta como uma linha de texto legível
+// Exemplo: "Linhares: 28.5 °C | 83.3 °F | 301.65 K"
+func encodeText(resp api.WeatherResponse) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s: %g °C | %g °F | %g K\n", resp.City, resp.TempC, resp.TempF, resp.TempK)), nil
+}
+
+// etag calcula uma ETag forte a partir do corpo já serializado
+// O tipo de mídia entra no cálculo para que cada representação tenha sua própria ETag
+func etag(f format, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(f.mediaType))
+	h.Write(body)
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// writeWeather envia a resposta de clima no formato negociado
+//
+// Em requisições GET, adiciona os cabeçalhos de cache (Cache-Control e ETag)
+// e responde 304 (Not Modified) quando o cliente já possui a representação
+// atual (If-None-Match). Respostas a clientes autenticados são marcadas como
+// private: apenas o cache do próprio cliente pode reaproveitá-las, nunca um
+// cache compartilhado (CDN ou proxy).
+func writeWeather(w http.ResponseWriter, r *http.Request, f format, resp api.WeatherResponse) error {
+	body, err := f.encode(resp)
+	if err != nil {
+		return err
+	}
+
+	// A resposta varia conforme o Accept do cliente
+	w.Header().Add("Vary", "Accept")
+
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		tag := etag(f, body)
+		w.Header().Set("ETag", tag)
+		scope := "public"
+		if _, ok := auth.FromContext(r.Context()); ok {
+			scope = "private"
+		}
+		w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(cacheMaxAge.Seconds())))
+		if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	w.Header().Set("Content-Type", f.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(body)
+	return err
+}
+
+// etagMatches verifica se alguma das ETags do cabeçalho If-None-Match corresponde à atual
+// Aceita o curinga "*" e ETags fracas (W/"..."), conforme a comparação fraca da RFC 9110
+func etagMatches(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", "application/json", true},
+		{"*/*", "application/json", true},
+		{"application/xml", "application/xml", true},
+		{"text/xml", "application/xml", true},
+		{"text/html, text/plain;q=0.9, */*;q=0.1", "text/plain", true},
+		{"application/json;q=0.5, application/xml", "application/xml", true},
+		{"text/plain, application/json", "text/plain", true},
+		{"text/html", "", false},
+		{"application/json;q=0", "", false},
+	}
+	for _, c := range cases {
+		f, ok := negotiate(c.accept)
+		if ok != c.ok || (ok && f.mediaType != c.want) {
+			t.Errorf("negotiate(%q): expected %s/%v, got %s/%v", c.accept, c.want, c.ok, f.mediaType, ok)
+		}
+	}
+}
+
+// newServiceB cria um Serviço B falso que responde sempre com o mesmo clima
+func newServiceB(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"city":"Linhares","temp_C":28.5,"temp_F":83.3,"temp_K":301.5}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetHandler_ContentNegotiation(t *testing.T) {
+	h := newGetHandler(newProxy(newServiceB(t).URL, time.Second, time.Second))
+
+	cases := map[string]string{
+		"application/json": `"city":"Linhares"`,
+		"application/xml":  `<weather><city>Linhares</city><temp_C>28.5</temp_C>`,
+		"text/plain":       "Linhares: 28.5 °C | 83.3 °F | 301.5 K",
+	}
+	for accept, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/weather/29902-555", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", accept, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, accept) {
+			t.Errorf("%s: unexpected content-type %s", accept, ct)
+		}
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("%s: expected body to contain %q, got %s", accept, want, rec.Body.String())
+		}
+		if rec.Header().Get("ETag") == "" || !strings.HasPrefix(rec.Header().Get("Cache-Control"), "public, max-age=") {
+			t.Errorf("%s: expected cache headers, got %v", accept, rec.Header())
+		}
+	}
+}
+
+func TestGetHandler_CacheControlWithAuth(t *testing.T) {
+	h := testAuthenticator(t).middleware("/weather/{cep}", newGetHandler(newProxy(newServiceB(t).URL, time.Second, time.Second)))
+
+	req := httptest.NewRequest(http.MethodGet, "/weather/29902555", nil)
+	req.Header.Set("X-API-Key", "key-acme")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	// Respostas autenticadas não podem ser reaproveitadas por caches compartilhados
+	if cc := rec.Header().Get("Cache-Control"); !strings.HasPrefix(cc, "private,") {
+		t.Errorf("expected private Cache-Control, got %q", cc)
+	}
+	vary := strings.Join(rec.Header().Values("Vary"), ", ")
+	for _, want := range []string{"Accept", "Authorization", "X-API-Key"} {
+		if !strings.Contains(vary, want) {
+			t.Errorf("expected Vary to include %s, got %q", want, vary)
+		}
+	}
+}
+
+func TestGetHandler_NotModified(t *testing.T) {
+	h := newGetHandler(newProxy(newServiceB(t).URL, time.Second, time.Second))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/weather/29902555", nil))
+	tag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/weather/29902555", nil)
+	req.Header.Set("If-None-Match", tag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("expected empty body, got %s", rec.Body.String())
+	}
+}
+
+func TestGetHandler_Errors(t *testing.T) {
+	h := newGetHandler(newProxy(newServiceB(t).URL, time.Second, time.Second))
+
+	cases := []struct {
+		method, path, accept string
+		status               int
+	}{
+		{http.MethodGet, "/weather/123", "", http.StatusUnprocessableEntity},
+		{http.MethodGet, "/weather/29902555", "image/png", http.StatusNotAcceptable},
+		{http.MethodDelete, "/weather/29902555", "", http.StatusMethodNotAllowed},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, nil)
+		req.Header.Set("Accept", c.accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.status {
+			t.Errorf("%s %s (%s): expected %d, got %d", c.method, c.path, c.accept, c.status, rec.Code)
+		}
+	}
+}
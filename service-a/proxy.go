@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"cep-weather/internal/api"
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/i18n"
+	"cep-weather/internal/problem"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// outcome classifica o resultado de uma chamada ao Serviço B
+// A classificação é registrada no span e define o status HTTP devolvido ao cliente
+type outcome string
+
+const (
+	outcomeOK          outcome = "ok"                 // Serviço B respondeu 2xx
+	outcomeClientError outcome = "client_error"       // Serviço B respondeu 4xx (erro do cliente, repassado)
+	outcomeServerError outcome = "server_error"       // Serviço B respondeu 5xx
+	outcomeRefused     outcome = "connection_refused" // Conexão recusada (serviço fora do ar)
+	outcomeTimeout     outcome = "timeout"            // Serviço B não respondeu dentro do prazo
+	outcomeNetwork     outcome = "network_error"      // Demais falhas de rede (DNS, conexão resetada, etc.)
+	outcomeCanceled    outcome = "canceled"           // Cliente cancelou a requisição antes da resposta
+	outcomeInvalid     outcome = "invalid_response"   // Serviço B respondeu 2xx com payload fora do contrato
+)
+
+// maxResponseSize limita o tamanho da resposta lida do Serviço B (1 MiB)
+const maxResponseSize = 1 << 20
+
+// kind retorna a categoria de erro devolvida ao cliente para cada classificação
+// - Conexão recusada: 503, o Serviço B está indisponível
+// - Timeout: 504, o Serviço B não respondeu a tempo
+// - 5xx, payload inválido ou falha de rede: 502, resposta inválida do serviço dependente
+func (o outcome) kind() problem.Kind {
+	switch o {
+	case outcomeRefused:
+		return problem.UpstreamUnavailable
+	case outcomeTimeout:
+		return problem.UpstreamTimeout
+	default:
+		return problem.BadGateway
+	}
+}
+
+// classifyError classifica um erro de transporte retornado por http.Client.Do
+func classifyError(err error) outcome {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return outcomeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return outcomeTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return outcomeTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return outcomeRefused
+	default:
+		return outcomeNetwork
+	}
+}
+
+// classifyStatus classifica uma resposta HTTP do Serviço B pelo código de status
+func classifyStatus(status int) outcome {
+	switch {
+	case status >= 500:
+		return outcomeServerError
+	case status >= 400:
+		return outcomeClientError
+	default:
+		return outcomeOK
+	}
+}
+
+// proxy encapsula a comunicação do Serviço A com o Serviço B
+// Mantém um único cliente (HTTP ou gRPC) instrumentado, reaproveitando conexões entre requisições
+type proxy struct {
+	url          string        // URL do endpoint de clima do Serviço B (ou endereço gRPC)
+	batchURL     string        // URL do endpoint de clima em lote do Serviço B
+	timeout      time.Duration // Tempo máximo de espera em consultas individuais
+	batchTimeout time.Duration // Tempo máximo de espera em consultas em lote
+	client       *http.Client  // Cliente HTTP instrumentado com OpenTelemetry
+
+	// Cliente gRPC do WeatherService; quando definido, substitui o HTTP
+	grpc weatherpb.WeatherServiceClient
+}
+
+// newProxy cria o proxy para o Serviço B
+// O endpoint de lote é derivado da URL principal: {url}/batch
+//
+// Parâmetros:
+//   - url: URL do endpoint de clima do Serviço B
+//   - timeout: Tempo máximo de espera por uma resposta individual (0 desativa o limite)
+//   - batchTimeout: Tempo máximo de espera por uma resposta em lote (0 desativa o limite)
+func newProxy(url string, timeout, batchTimeout time.Duration) *proxy {
+	return &proxy{
+		url:          url,
+		batchURL:     strings.TrimSuffix(url, "/") + "/batch",
+		timeout:      timeout,
+		batchTimeout: batchTimeout,
+		// O transporte OTEL automaticamente cria spans para requisições HTTP
+		// e propaga o contexto de rastreamento distribuído
+		client: &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
+}
+
+// forward consulta o clima de um CEP no Serviço B
+//
+// Retorna:
+//   - api.WeatherResponse: Resposta validada do Serviço B
+//   - bool: false quando a chamada falhou e a resposta de erro já foi enviada ao cliente
+func (p *proxy) forward(ctx context.Context, w http.ResponseWriter, r *http.Request, req api.WeatherRequest) (api.WeatherResponse, bool) {
+	resp, uerr := p.weather(ctx, req)
+	return resp, p.respond(ctx, w, r, uerr)
+}
+
+// forwardBatch consulta o clima de um lote de CEPs no Serviço B
+//
+// Retorna:
+//   - api.BatchResponse: Resposta validada do Serviço B (um resultado por CEP)
+//   - bool: false quando a chamada falhou e a resposta de erro já foi enviada ao cliente
+func (p *proxy) forwardBatch(ctx context.Context, w http.ResponseWriter, r *http.Request, req api.BatchRequest) (api.BatchResponse, bool) {
+	resp, uerr := p.batch(ctx, req)
+	return resp, p.respond(ctx, w, r, uerr)
+}
+
+// weather consulta o clima de um CEP no Serviço B, via HTTP ou gRPC, sem responder ao cliente
+func (p *proxy) weather(ctx context.Context, req api.WeatherRequest) (api.WeatherResponse, *upstreamError) {
+	if p.grpc != nil {
+		return p.grpcWeather(ctx, req)
+	}
+	var resp api.WeatherResponse
+	uerr := p.do(ctx, p.url, p.timeout, req, func(body io.Reader) (err error) {
+		resp, err = api.DecodeWeatherResponse(body)
+		return err
+	})
+	return resp, uerr
+}
+
+// batch consulta o clima de um lote de CEPs no Serviço B, via HTTP ou gRPC, sem responder ao cliente
+func (p *proxy) batch(ctx context.Context, req api.BatchRequest) (api.BatchResponse, *upstreamError) {
+	if p.grpc != nil {
+		return p.grpcBatch(ctx, req)
+	}
+	var resp api.BatchResponse
+	uerr := p.do(ctx, p.batchURL, p.batchTimeout, req, func(body io.Reader) (err error) {
+		resp, err = api.DecodeBatchResponse(body, len(req.CEPs))
+		return err
+	})
+	return resp, uerr
+}
+
+// upstreamError descreve uma chamada ao Serviço B que não resultou em sucesso
+type upstreamError struct {
+	outcome outcome // Classificação da falha
+	detail  string  // Detalhe exposto ao cliente
+	err     error   // Erro original, registrado no span
+
+	// Preenchidos apenas para respostas 4xx, que são repassadas ao cliente
+	status      int    // Status HTTP devolvido pelo Serviço B
+	contentType string // Content-type da resposta de erro
+	body        []byte // Corpo da resposta de erro
+
+	// Categoria do erro do cliente em chamadas gRPC, derivada do código de status
+	kind problem.Kind
+}
+
+// respond envia ao cliente a resposta de erro correspondente à falha
+// - 4xx é repassado ao cliente com o mesmo status, content-type e corpo
+// - 5xx, payload inválido e falhas de transporte são convertidos em 502/503/504
+//
+// Retorna false quando a chamada falhou e a resposta de erro já foi enviada ao cliente
+func (p *proxy) respond(ctx context.Context, w http.ResponseWriter, r *http.Request, uerr *upstreamError) bool {
+	switch {
+	case uerr == nil:
+		return true
+	case uerr.outcome == outcomeCanceled:
+		// O cliente desistiu da requisição; não há a quem responder
+		return false
+	case uerr.outcome != outcomeClientError:
+		problem.Write(w, r, uerr.outcome.kind(), uerr.detail)
+		return false
+	case uerr.kind.Code != "":
+		// Erros gRPC não têm corpo a repassar; o Problem é montado a partir
+		// da categoria derivada do código de status
+		problem.Write(w, r, uerr.kind, uerr.detail)
+		return false
+	}
+
+	// Repassa o código de status e o corpo da resposta de erro do Serviço B
+	// O content-type original é mantido (application/problem+json ou
+	// text/plain no modo legado)
+	if uerr.contentType != "" {
+		w.Header().Set("Content-Type", uerr.contentType)
+	}
+	w.WriteHeader(uerr.status)
+
+	// Após WriteHeader não é mais possível alterar o status da resposta,
+	// então uma falha na escrita é apenas registrada no span e no log
+	if _, err := w.Write(uerr.body); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		log.Printf("Erro ao repassar a resposta do Serviço B: %v", err)
+	}
+	return false
+}
+
+// do envia o payload JSON ao Serviço B e interpreta a resposta, sem responder ao cliente
+//
+// O resultado da chamada é classificado (sucesso, 4xx, 5xx, conexão recusada,
+// timeout) e registrado no span atual como atributos e status. Respostas 2xx
+// são decodificadas e validadas pela função decode.
+//
+// Retorna nil em caso de sucesso
+func (p *proxy) do(ctx context.Context, url string, timeout time.Duration, payload any, decode func(io.Reader) error) *upstreamError {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("serviceb.protocol", "http"),
+		attribute.String("serviceb.url", url),
+	)
+
+	// fail registra a falha no span e monta o erro correspondente
+	fail := func(o outcome, err error, detail string) *upstreamError {
+		span.SetAttributes(attribute.String("serviceb.outcome", string(o)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return &upstreamError{outcome: o, detail: detail, err: err}
+	}
+
+	// O prazo cobre o envio da requisição e a leitura completa da resposta
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Converte a requisição para JSON para enviar ao Serviço B
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fail(outcomeNetwork, err, "")
+	}
+
+	// Cria a requisição HTTP POST com contexto para propagação de traces
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fail(outcomeNetwork, err, "")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	// Repassa o idioma negociado com o cliente: respostas 4xx do Serviço B
+	// são devolvidas ao cliente sem alteração e já chegam traduzidas
+	httpReq.Header.Set("Accept-Language", string(i18n.FromContext(ctx)))
+
+	// Envia a requisição para o Serviço B
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		o := classifyError(err)
+		return fail(o, err, fmt.Sprintf("service-b request failed: %s", o))
+	}
+	defer resp.Body.Close() // Garante que o body será fechado
+
+	o := classifyStatus(resp.StatusCode)
+	span.SetAttributes(
+		attribute.String("serviceb.outcome", string(o)),
+		attribute.Int("serviceb.status_code", resp.StatusCode),
+	)
+
+	switch o {
+	case outcomeOK:
+		// Valida o payload antes de repassá-lo: uma resposta fora do contrato
+		// (JSON malformado, cidade vazia, temperatura ausente) vira 502
+		if err := decode(io.LimitReader(resp.Body, maxResponseSize)); err != nil {
+			return fail(outcomeInvalid, err, "service-b returned an invalid weather response")
+		}
+		span.SetStatus(codes.Ok, "")
+		return nil
+
+	case outcomeServerError:
+		// Descarta o corpo para permitir o reaproveitamento da conexão
+		io.Copy(io.Discard, resp.Body)
+		err := fmt.Errorf("service-b responded with status %d", resp.StatusCode)
+		return fail(o, err, err.Error())
+	}
+
+	// Erros do cliente (4xx) não marcam o span como erro: o Serviço B
+	// funcionou corretamente, o problema está na requisição
+	errBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fail(outcomeNetwork, err, "")
+	}
+	return &upstreamError{
+		outcome:     o,
+		err:         fmt.Errorf("service-b responded with status %d", resp.StatusCode),
+		status:      resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        errBody,
+	}
+}
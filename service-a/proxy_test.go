@@ -0,0 +1,168 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setupTracer registra um TracerProvider em memória para inspecionar os spans gerados
+func setupTracer(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	orig := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(orig) })
+	return sr
+}
+
+// processSpan retorna o span "process-zipcode" gerado pelo handler
+func processSpan(t *testing.T, sr *tracetest.SpanRecorder) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, s := range sr.Ended() {
+		if s.Name() == "process-zipcode" {
+			return s
+		}
+	}
+	t.Fatalf("span process-zipcode não encontrado")
+	return nil
+}
+
+// outcomeOf retorna o atributo serviceb.outcome registrado no span
+func outcomeOf(s sdktrace.ReadOnlySpan) string {
+	for _, kv := range s.Attributes() {
+		if kv.Key == "serviceb.outcome" {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+// doRequest executa uma requisição POST /weather contra o handler do Serviço A
+func doRequest(p *proxy, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	newPostHandler(p).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestProxy_Success(t *testing.T) {
+	sr := setupTracer(t)
+	var received api.WeatherRequest
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"city":"Linhares","temp_C":28.5,"temp_F":83.3,"temp_K":301.5}`))
+	}))
+	defer serviceB.Close()
+
+	rec := doRequest(newProxy(serviceB.URL, time.Second, time.Second), `{"cep":" 29902-555 "}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if received.CEP != "29902555" {
+		t.Fatalf("expected normalized cep to be sent to service-b, got %q", received.CEP)
+	}
+	if !strings.Contains(rec.Body.String(), `"city":"Linhares"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+	span := processSpan(t, sr)
+	if span.Status().Code != codes.Ok || outcomeOf(span) != "ok" {
+		t.Fatalf("expected ok span, got status=%v outcome=%s", span.Status(), outcomeOf(span))
+	}
+}
+
+func TestProxy_ClientErrorIsForwarded(t *testing.T) {
+	sr := setupTracer(t)
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"zipcode_not_found","status":404}`))
+	}))
+	defer serviceB.Close()
+
+	rec := doRequest(newProxy(serviceB.URL, time.Second, time.Second), `{"cep":"29902555"}`)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("expected problem content-type to be forwarded, got %s", ct)
+	}
+	span := processSpan(t, sr)
+	if span.Status().Code == codes.Error || outcomeOf(span) != "client_error" {
+		t.Fatalf("expected client_error without error status, got status=%v outcome=%s", span.Status(), outcomeOf(span))
+	}
+}
+
+func TestProxy_Failures(t *testing.T) {
+	// Serviço B que responde 500
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	// Serviço B que responde 200 com payload fora do contrato
+	malformed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"city":"","temp_C":"hot"}`))
+	}))
+	defer malformed.Close()
+
+	// Serviço B que demora mais do que o timeout configurado
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+
+	// Endereço sem servidor escutando (conexão recusada)
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	cases := []struct {
+		name    string
+		url     string
+		status  int
+		outcome string
+	}{
+		{"server error", failing.URL, http.StatusBadGateway, "server_error"},
+		{"invalid response", malformed.URL, http.StatusBadGateway, "invalid_response"},
+		{"timeout", slow.URL, http.StatusGatewayTimeout, "timeout"},
+		{"connection refused", closedURL, http.StatusServiceUnavailable, "connection_refused"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sr := setupTracer(t)
+
+			rec := doRequest(newProxy(c.url, 50*time.Millisecond, 50*time.Millisecond), `{"cep":"29902555"}`)
+
+			if rec.Code != c.status {
+				t.Fatalf("expected %d, got %d", c.status, rec.Code)
+			}
+			span := processSpan(t, sr)
+			if span.Status().Code != codes.Error {
+				t.Fatalf("expected error span status, got %v", span.Status())
+			}
+			if got := outcomeOf(span); got != c.outcome {
+				t.Fatalf("expected outcome %s, got %s", c.outcome, got)
+			}
+			if len(span.Events()) == 0 {
+				t.Fatalf("expected error to be recorded on span")
+			}
+		})
+	}
+}
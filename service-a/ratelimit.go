@@ -0,0 +1,241 @@
+package main
+
+import (
+	"cep-weather/internal/auth"
+	"cep-weather/internal/config"
+	"cep-weather/internal/problem"
+	"cep-weather/internal/ratelimit"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// client identifica quem fez a requisição, para fins de cota
+type client struct {
+	ID   string // Chave da contagem: "key:<Client.KeyID do cliente autenticado>" ou "ip:<endereço>"
+	Tier string // Plano do cliente (ver config.InboundLimit)
+}
+
+// quotaLimiter aplica as cotas de requisições por cliente
+//
+// Cada cliente tem uma janela fixa por plano; ao exceder a cota, recebe 429
+// (Too Many Requests) com Retry-After. Todas as respostas informam a cota nos
+// cabeçalhos RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset e
+// RateLimit-Policy (draft-ietf-httpapi-ratelimit-headers).
+//
+// As credenciais recusadas também são contadas, por IP, com a cota do plano
+// anônimo (ver allowAttempt), limitando a tentativa de adivinhar chaves de
+// API e tokens.
+type quotaLimiter struct {
+	store         ratelimit.Store
+	tiers         map[string]ratelimit.Quota
+	trusted       []*net.IPNet // Proxies confiáveis (ver clientIP)
+	anonymousTier string       // Plano dos clientes sem credencial
+	keyTier       string       // Plano dos clientes autenticados sem plano conhecido
+}
+
+// newQuotaLimiter cria o limitador a partir da configuração
+//
+// Parâmetros:
+//   - cfg: Planos e proxies confiáveis (já validados)
+//   - store: Contadores das cotas (ratelimit.NewMemoryStore ou um Store compartilhado)
+func newQuotaLimiter(cfg config.InboundLimit, store ratelimit.Store) *quotaLimiter {
+	tiers := make(map[string]ratelimit.Quota, len(cfg.Tiers))
+	for name, t := range cfg.Tiers {
+		tiers[name] = ratelimit.Quota{Limit: t.Requests, Window: time.Duration(t.Window)}
+	}
+	var trusted []*net.IPNet
+	for _, p := range cfg.TrustedProxies {
+		if n, err := config.ParseNetwork(p); err == nil {
+			trusted = append(trusted, n)
+		}
+	}
+	return &quotaLimiter{store: store, tiers: tiers, trusted: trusted, anonymousTier: cfg.AnonymousTier, keyTier: cfg.KeyTier}
+}
+
+// identify identifica o cliente da requisição
+//
+// Clientes autenticados (ver authenticator) são contados pela credencial,
+// com o plano do arquivo de chaves ou do token, desde que ele exista entre
+// os planos configurados; os demais, pelo IP. Uma chave de API não validada
+// é ignorada: do contrário, trocar a chave a cada requisição escaparia da
+// cota por IP
+func (l *quotaLimiter) identify(r *http.Request) client {
+	if c, ok := auth.FromContext(r.Context()); ok {
+		tier := c.Tier
+		if _, known := l.tiers[tier]; !known {
+			tier = l.keyTier
+		}
+		return client{ID: "key:" + c.KeyID, Tier: tier}
+	}
+	return l.anonymous(r)
+}
+
+// anonymous identifica o cliente pelo IP, com o plano anônimo
+func (l *quotaLimiter) anonymous(r *http.Request) client {
+	return client{ID: "ip:" + clientIP(r, l.trusted), Tier: l.anonymousTier}
+}
+
+// middleware conta a requisição na cota do cliente antes de repassá-la
+// O handler pode cobrar unidades adicionais da mesma cota (ver chargeQuota)
+func (l *quotaLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := l.identify(r)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("ratelimit.tier", c.Tier))
+		key, quota := c.Tier+"|"+c.ID, l.tiers[c.Tier]
+		if !l.charge(w, r, key, quota, 1) {
+			return
+		}
+		extra := quotaCharge(func(w http.ResponseWriter, r *http.Request, cost int) bool {
+			return l.charge(w, r, key, quota, cost)
+		})
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), quotaChargeKey{}, extra)))
+	})
+}
+
+// quotaCharge cobra unidades adicionais da cota do cliente da requisição
+type quotaCharge func(w http.ResponseWriter, r *http.Request, cost int) bool
+
+// quotaChargeKey é a chave do quotaCharge no contexto da requisição
+type quotaChargeKey struct{}
+
+// chargeQuota cobra cost unidades adicionais da cota do cliente, além da
+// unidade cobrada por requisição (ex: um lote custa uma unidade por CEP)
+// Retorna false, após responder 429, quando a cota foi excedida; sem cotas
+// ativas, sempre retorna true
+func chargeQuota(w http.ResponseWriter, r *http.Request, cost int) bool {
+	charge, ok := r.Context().Value(quotaChargeKey{}).(quotaCharge)
+	if !ok || cost <= 0 {
+		return true
+	}
+	return charge(w, r, cost)
+}
+
+// charge soma cost requisições ao contador e informa a cota nos cabeçalhos
+// Retorna false, após responder 429, quando a cota foi excedida. Nesse caso as
+// unidades são devolvidas: um lote maior que a cota restante é recusado sem
+// consumi-la, e as requisições seguintes continuam sendo atendidas.
+func (l *quotaLimiter) charge(w http.ResponseWriter, r *http.Request, key string, quota ratelimit.Quota, cost int) bool {
+	span := trace.SpanFromContext(r.Context())
+
+	// Falhas do Store não devem derrubar a API: a requisição segue sem cota
+	count, reset, err := l.store.Incr(r.Context(), key, cost, quota.Window)
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("Erro ao consultar a cota do cliente: %v", err)
+		return true
+	}
+
+	remaining := quota.Limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	resetSeconds := retryAfter(reset)
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(quota.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	h.Set("RateLimit-Reset", resetSeconds)
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", quota.Limit, int(quota.Window.Seconds())))
+	span.SetAttributes(attribute.Int("ratelimit.remaining", remaining))
+
+	if count > quota.Limit {
+		if _, _, err := l.store.Incr(r.Context(), key, -cost, quota.Window); err != nil {
+			span.RecordError(err)
+			log.Printf("Erro ao devolver a cota do cliente: %v", err)
+		}
+		remaining = quota.Limit - (count - cost)
+		if remaining < 0 {
+			remaining = 0
+		}
+		h.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		span.SetAttributes(attribute.Int("ratelimit.remaining", remaining))
+		h.Set("Retry-After", resetSeconds)
+		span.AddEvent("ratelimit.exceeded")
+		problem.Write(w, r, problem.RateLimited, "request quota exceeded; retry after the time indicated in Retry-After")
+		return false
+	}
+	return true
+}
+
+// allowAttempt informa se o IP ainda pode apresentar credenciais
+// Depois de tantas credenciais recusadas quanto a cota do plano anônimo, as
+// tentativas seguintes recebem 429 até o fim da janela, sem que a credencial
+// seja verificada
+func (l *quotaLimiter) allowAttempt(w http.ResponseWriter, r *http.Request) bool {
+	c := l.anonymous(r)
+	quota := l.tiers[c.Tier]
+	count, reset, err := l.store.Incr(r.Context(), attemptKey(c), 0, quota.Window)
+	if err != nil || count < quota.Limit {
+		return true
+	}
+	w.Header().Set("Retry-After", retryAfter(reset))
+	trace.SpanFromContext(r.Context()).AddEvent("ratelimit.auth_attempts_exceeded")
+	problem.Write(w, r, problem.RateLimited, "too many failed authentication attempts; retry after the time indicated in Retry-After")
+	return false
+}
+
+// failedAttempt conta uma credencial recusada para o IP da requisição
+func (l *quotaLimiter) failedAttempt(r *http.Request) {
+	c := l.anonymous(r)
+	if _, _, err := l.store.Incr(r.Context(), attemptKey(c), 1, l.tiers[c.Tier].Window); err != nil {
+		log.Printf("Erro ao registrar a tentativa de autenticação: %v", err)
+	}
+}
+
+// attemptKey é o contador das credenciais recusadas do IP
+func attemptKey(c client) string {
+	return "auth-failures|" + c.ID
+}
+
+// retryAfter retorna os segundos até reset, arredondados para cima
+func retryAfter(reset time.Time) string {
+	return strconv.Itoa(int(math.Ceil(time.Until(reset).Seconds())))
+}
+
+// clientIP retorna o IP do cliente que fez a requisição
+//
+// X-Forwarded-For só é considerado quando a conexão parte de um proxy
+// confiável; nesse caso, o cabeçalho é percorrido da direita para a esquerda
+// e o primeiro endereço que não pertence a um proxy confiável é o do cliente.
+// Assim, um cliente não consegue escolher o próprio IP forjando o cabeçalho.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !isTrusted(ip, trusted) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !isTrusted(hop, trusted) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrusted informa se o IP pertence a um dos proxies confiáveis
+func isTrusted(raw string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(raw)
+	for _, n := range trusted {
+		if ip != nil && n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
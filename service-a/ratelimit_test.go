@@ -0,0 +1,136 @@
+package main
+
+import (
+	"cep-weather/internal/auth"
+	"cep-weather/internal/config"
+	"cep-weather/internal/ratelimit"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLimits cria cotas pequenas para os testes: 2 requisições anônimas e 3
+// com chave de API por minuto
+func testLimits() config.InboundLimit {
+	cfg := config.DefaultServiceA().RateLimit
+	cfg.TrustedProxies = []string{"10.0.0.0/8"}
+	cfg.Tiers = map[string]config.Tier{
+		"anonymous": {Requests: 2, Window: config.Duration(time.Minute)},
+		"standard":  {Requests: 3, Window: config.Duration(time.Minute)},
+	}
+	return cfg
+}
+
+func TestQuotaLimiter(t *testing.T) {
+	h := newQuotaLimiter(testLimits(), ratelimit.NewMemoryStore()).middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// key simula um cliente autenticado pelo authenticator
+	send := func(remoteAddr, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/weather/01310100", nil)
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			ctx := auth.WithClient(req.Context(), auth.Client{Name: "acme", KeyID: auth.KeyID(key)})
+			req = req.WithContext(ctx)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i, want := range []string{"1", "0"} {
+		rec := send("203.0.113.1:1234", "")
+		if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Remaining") != want {
+			t.Fatalf("request %d: expected 200 with %s remaining, got %d/%s", i, want, rec.Code, rec.Header().Get("RateLimit-Remaining"))
+		}
+	}
+
+	rec := send("203.0.113.1:1234", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after the quota, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" || rec.Header().Get("RateLimit-Limit") != "2" || rec.Header().Get("RateLimit-Policy") != "2;w=60" {
+		t.Fatalf("unexpected headers: %v", rec.Header())
+	}
+
+	// Uma chave de API não validada não abre uma cota nova
+	req := httptest.NewRequest(http.MethodGet, "/weather/01310100", nil)
+	req.RemoteAddr = "203.0.113.1:1234"
+	req.Header.Set("X-API-Key", "chave-inventada")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected unvalidated key to count against the IP, got %d", rec.Code)
+	}
+
+	// Outro IP e clientes autenticados têm cotas próprias
+	if rec := send("203.0.113.2:1234", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected independent quota per IP, got %d", rec.Code)
+	}
+	if rec := send("203.0.113.1:1234", "key-1"); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "3" {
+		t.Fatalf("expected key tier quota, got %d/%s", rec.Code, rec.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, _ := config.ParseNetwork("10.0.0.0/8")
+	cases := []struct {
+		name, remote, xff, want string
+	}{
+		{"direct", "203.0.113.1:1234", "", "203.0.113.1"},
+		{"untrusted proxy is ignored", "203.0.113.1:1234", "198.51.100.7", "203.0.113.1"},
+		{"trusted proxy", "10.0.0.1:1234", "198.51.100.7", "198.51.100.7"},
+		{"spoofed hops on the left", "10.0.0.1:1234", "1.2.3.4, 198.51.100.7, 10.0.0.2", "198.51.100.7"},
+		{"trusted proxy without header", "10.0.0.1:1234", "", "10.0.0.1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.remote
+			if tc.xff != "" {
+				req.Header.Set("X-Forwarded-For", tc.xff)
+			}
+			if got := clientIP(req, []*net.IPNet{trusted}); got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestQuotaLimiter_Batch(t *testing.T) {
+	l := newQuotaLimiter(testLimits(), ratelimit.NewMemoryStore())
+	h := l.middleware(newBatchHandler(newProxy(newServiceB(t).URL, time.Second, time.Second), 2))
+
+	send := func(remoteAddr, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Cada CEP custa uma unidade: um lote de 2 esgota a cota anônima dos testes
+	rec := send("203.0.113.1:1234", `{"ceps":["29902555","01310-100"]}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("expected batch to consume the quota, got %d/%s", rec.Code, rec.Header().Get("RateLimit-Remaining"))
+	}
+	if rec := send("203.0.113.1:1234", `{"ceps":["29902555"]}`); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after the batch, got %d", rec.Code)
+	}
+	// Um lote maior que a cota é recusado antes de consultar o Serviço B
+	rec = send("203.0.113.2:1234", `{"ceps":["29902555","01310-100","20040-020"]}`)
+	if rec.Code != http.StatusTooManyRequests || strings.Contains(rec.Body.String(), "Linhares") {
+		t.Fatalf("expected oversized batch to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+	// O lote recusado não consome a cota: uma consulta seguinte é atendida
+	get := httptest.NewRequest(http.MethodGet, "/weather/29902555", nil)
+	get.RemoteAddr = "203.0.113.2:1234"
+	rec = httptest.NewRecorder()
+	l.middleware(newGetHandler(newProxy(newServiceB(t).URL, time.Second, time.Second))).ServeHTTP(rec, get)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected GET after a rejected batch to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader é o cabeçalho com o identificador da requisição
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID aceita identificadores curtos e seguros para logs e baggage
+// (ex: UUIDs); outros valores são substituídos por um identificador gerado
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// requestID identifica cada requisição recebida pelo Serviço A
+//
+// O identificador informado pelo cliente em X-Request-ID é mantido quando
+// válido; caso contrário, um novo é gerado. Ele é devolvido no mesmo
+// cabeçalho, registrado no atributo request.id do span e incluído no
+// baggage, que o leva até o Serviço B.
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ctx := r.Context()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", id))
+		if m, err := baggage.NewMember("request.id", id); err == nil {
+			if b, err := baggage.FromContext(ctx).SetMember(m); err == nil {
+				ctx = baggage.ContextWithBaggage(ctx, b)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// newRequestID gera um identificador aleatório de 128 bits, em hexadecimal
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestRequestID(t *testing.T) {
+	var inBaggage string
+	h := requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inBaggage = baggage.FromContext(r.Context()).Member("request.id").Value()
+	}))
+
+	cases := map[string]bool{
+		"":                                     false,
+		"4f2c6a1e-9b7d-4c1e-8a3f-0d5e6b7c8a9b": true,
+		"id with spaces":                       false,
+		strings.Repeat("a", 65):                false,
+	}
+	for sent, kept := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/weather/01310100", nil)
+		if sent != "" {
+			req.Header.Set("X-Request-ID", sent)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		got := rec.Header().Get("X-Request-ID")
+		if got != inBaggage || !validRequestID.MatchString(got) {
+			t.Errorf("%q: expected valid id in header and baggage, got %q / %q", sent, got, inBaggage)
+		}
+		if (got == sent) != kept {
+			t.Errorf("%q: expected kept=%v, got %q", sent, kept, got)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/problem"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// streamItem é um resultado do lote enviado no modo streaming
+// Como os itens são enviados na ordem em que ficam prontos, o campo index
+// indica a posição do CEP na requisição original
+type streamItem struct {
+	Index int `json:"index"`
+	api.BatchItem
+}
+
+// batchFormat representa um formato de resposta do endpoint de lote
+// Formatos com write definido são enviados em streaming, item a item
+type batchFormat struct {
+	mediaType string                                   // Tipo de mídia (Content-Type) da resposta
+	write     func(w io.Writer, item streamItem) error // Serializa um item do lote
+	done      func(w io.Writer, count int) error       // Sinaliza o fim do lote
+}
+
+// streaming indica se o formato envia os itens conforme ficam prontos
+func (f batchFormat) streaming() bool {
+	return f.write != nil
+}
+
+// Formatos suportados pelo endpoint de lote
+var (
+	// batchJSON é o formato padrão: um único JSON com todos os resultados
+	batchJSON = batchFormat{mediaType: "application/json"}
+	// batchNDJSON envia um JSON por linha (newline-delimited JSON)
+	batchNDJSON = batchFormat{mediaType: "application/x-ndjson", write: writeNDJSON, done: func(io.Writer, int) error { return nil }}
+	// batchSSE envia cada item como um evento "result" (Server-Sent Events)
+	batchSSE = batchFormat{mediaType: "text/event-stream", write: writeSSE, done: doneSSE}
+)
+
+// batchFormats mapeia os tipos de mídia aceitos no cabeçalho Accept para os formatos de lote
+var batchFormats = map[string]batchFormat{
+	"application/json":     batchJSON,
+	"application/*":        batchJSON,
+	"*/*":                  batchJSON,
+	"application/x-ndjson": batchNDJSON,
+	"application/jsonl":    batchNDJSON,
+	"text/event-stream":    batchSSE,
+}
+
+// writeNDJSON escreve o item como uma linha JSON
+func writeNDJSON(w io.Writer, item streamItem) error {
+	return json.NewEncoder(w).Encode(item)
+}
+
+// writeSSE escreve o item como um evento SSE, usando o índice como id
+func writeSSE(w io.Writer, item streamItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: result\nid: %d\ndata: %s\n\n", item.Index, data)
+	return err
+}
+
+// doneSSE envia o evento "done" com a quantidade de itens enviados
+func doneSSE(w io.Writer, count int) error {
+	_, err := fmt.Fprintf(w, "event: done\ndata: {\"count\":%d}\n\n", count)
+	return err
+}
+
+// streamBatch consulta os CEPs do lote e envia cada resultado assim que fica pronto
+//
+// Cada CEP é consultado individualmente no Serviço B, em um span "stream-item"
+// próprio cujo contexto é propagado na chamada, mantendo o trace de cada item.
+// No máximo "concurrency" CEPs são consultados em paralelo.
+//
+// Se o cliente desconectar, o contexto da requisição é cancelado: nenhum novo
+// CEP é consultado e as chamadas em andamento ao Serviço B são interrompidas.
+func streamBatch(p *proxy, w http.ResponseWriter, r *http.Request, req api.BatchRequest, f batchFormat, concurrency int) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		problem.Write(w, r, problem.Internal, "streaming is not supported by the server")
+		return
+	}
+
+	tracer := otel.Tracer("service-a")
+	ctx, span := tracer.Start(r.Context(), "process-batch-stream")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("batch.size", len(req.CEPs)),
+		attribute.String("stream.format", f.mediaType),
+	)
+
+	// Cancelado quando o cliente desconecta ou quando a escrita falha
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w.Header().Set("Content-Type", f.mediaType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Desativa o buffering em proxies reversos (nginx)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Despacha as consultas com concorrência limitada; os resultados chegam
+	// pelo canal na ordem em que ficam prontos
+	results := make(chan streamItem)
+	go func() {
+		defer close(results)
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+	dispatch:
+		for i, raw := range req.CEPs {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				break dispatch
+			}
+			wg.Add(1)
+			go func(i int, raw string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				item := p.streamItem(ctx, i, raw)
+				select {
+				case results <- item:
+				case <-ctx.Done():
+				}
+			}(i, raw)
+		}
+		wg.Wait()
+	}()
+
+	// Apenas esta goroutine escreve na resposta
+	sent := 0
+	for item := range results {
+		if ctx.Err() != nil {
+			continue // Drena o canal até que os workers terminem
+		}
+		if err := f.write(w, item); err != nil {
+			span.RecordError(err)
+			cancel()
+			continue
+		}
+		flusher.Flush()
+		sent++
+	}
+
+	span.SetAttributes(attribute.Int("stream.sent", sent))
+	if err := ctx.Err(); err != nil {
+		span.SetAttributes(attribute.Bool("stream.canceled", true))
+		span.SetStatus(codes.Error, "stream canceled before completion")
+		return
+	}
+	if err := f.done(w, sent); err != nil {
+		span.RecordError(err)
+		return
+	}
+	flusher.Flush()
+	span.SetStatus(codes.Ok, "")
+}
+
+// streamItem consulta o clima de um CEP do lote em um span próprio
+// O contexto do span é propagado ao Serviço B pelo cliente instrumentado (HTTP ou gRPC)
+func (p *proxy) streamItem(ctx context.Context, index int, raw string) streamItem {
+	tracer := otel.Tracer("service-a")
+	ctx, span := tracer.Start(ctx, "stream-item", trace.WithAttributes(
+		attribute.Int("batch.index", index),
+		attribute.String("cep", raw),
+	))
+	defer span.End()
+
+	item := streamItem{Index: index, BatchItem: api.BatchItem{CEP: raw}}
+
+	// CEPs inválidos são respondidos localmente, sem acionar o Serviço B
+	c, err := cep.Parse(raw)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		pr := problem.New(ctx, problem.InvalidZipcode, err.Error())
+		item.Error = &pr
+		return item
+	}
+	span.SetAttributes(attribute.String("cep.uf", c.State()))
+
+	resp, uerr := p.weather(ctx, api.WeatherRequest{CEP: c.String()})
+	if uerr != nil {
+		pr := uerr.problem(ctx)
+		item.Error = &pr
+		return item
+	}
+	item.Weather = &resp
+	return item
+}
+
+// problem converte a falha em um Problem, para ser enviado como item do lote
+// Erros 4xx reaproveitam o Problem devolvido pelo Serviço B (ou a categoria
+// derivada do código de status, em chamadas gRPC); no modo legado
+// (texto puro), a categoria é inferida pelo status HTTP
+func (e *upstreamError) problem(ctx context.Context) problem.Problem {
+	if e.outcome != outcomeClientError {
+		return problem.New(ctx, e.outcome.kind(), e.detail)
+	}
+	if e.kind.Code != "" {
+		return problem.New(ctx, e.kind, e.detail)
+	}
+
+	var p problem.Problem
+	if err := json.Unmarshal(e.body, &p); err == nil && p.Code != "" {
+		return p
+	}
+	switch e.status {
+	case http.StatusNotFound:
+		return problem.New(ctx, problem.ZipcodeNotFound, "")
+	case http.StatusUnprocessableEntity:
+		return problem.New(ctx, problem.InvalidZipcode, "")
+	default:
+		return problem.New(ctx, problem.BadGateway, e.err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"cep-weather/internal/api"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchHandler_NDJSON(t *testing.T) {
+	sr := setupTracer(t)
+	h := newBatchHandler(newProxy(newServiceB(t).URL, time.Second, time.Second), 2)
+
+	req := httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(`{"ceps":["29902555","123","01310-100"]}`))
+	req.Header.Set("Accept", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("expected application/x-ndjson, got %s", ct)
+	}
+
+	seen := map[int]streamItem{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var item streamItem
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		seen[item.Index] = item
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(seen))
+	}
+	if seen[0].Weather == nil || seen[0].Weather.City != "Linhares" || seen[0].CEP != "29902555" {
+		t.Errorf("unexpected item 0: %+v", seen[0])
+	}
+	if seen[1].Error == nil || seen[1].Error.Code != "invalid_zipcode" {
+		t.Errorf("expected invalid_zipcode for item 1, got %+v", seen[1])
+	}
+
+	// Cada CEP válido gera um span próprio, com a chamada ao Serviço B como filha
+	items := 0
+	for _, s := range sr.Ended() {
+		if s.Name() == "stream-item" {
+			items++
+		}
+	}
+	if items != 3 {
+		t.Errorf("expected 3 stream-item spans, got %d", items)
+	}
+}
+
+func TestBatchHandler_SSE(t *testing.T) {
+	h := newBatchHandler(newProxy(newServiceB(t).URL, time.Second, time.Second), 2)
+
+	req := httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(`{"ceps":["29902555","01310-100"]}`))
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if strings.Count(body, "event: result\n") != 2 {
+		t.Fatalf("expected 2 result events, got: %s", body)
+	}
+	if !strings.HasSuffix(body, "event: done\ndata: {\"count\":2}\n\n") {
+		t.Fatalf("expected done event at the end, got: %s", body)
+	}
+}
+
+func TestBatchHandler_StreamCanceled(t *testing.T) {
+	calls := make(chan struct{}, 100)
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	h := newBatchHandler(newProxy(slow.URL, 10*time.Second, 10*time.Second), 2)
+
+	ceps := make([]string, 20)
+	for i := range ceps {
+		ceps[i] = "01310100"
+	}
+	body, _ := json.Marshal(api.BatchRequest{CEPs: ceps})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(string(body))).WithContext(ctx)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		close(done)
+	}()
+
+	// Simula a desconexão do cliente assim que as primeiras consultas começam
+	<-calls
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("handler did not stop after client disconnect")
+	}
+	// Apenas as consultas já em andamento (até a concorrência) chegam ao Serviço B
+	if n := len(calls) + 1; n > 2 {
+		t.Fatalf("expected at most 2 service-b calls, got %d", n)
+	}
+}
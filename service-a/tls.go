@@ -0,0 +1,78 @@
+package main
+
+import (
+	"cep-weather/internal/certs"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// peerSubjectKey é o atributo do span com o subject do certificado do
+// Serviço B (ex: "CN=service-b,O=cep-weather"), presente quando há TLS
+const peerSubjectKey = attribute.Key("tls.server.subject")
+
+// useTLS passa a chamar o Serviço B por HTTPS com a configuração informada
+// (ver certs.Reloader.ClientConfig)
+func (p *proxy) useTLS(cfg *tls.Config) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	p.client = &http.Client{Transport: otelhttp.NewTransport(peerTransport{next: transport})}
+}
+
+// serverName retorna o nome esperado no certificado do Serviço B: o
+// configurado (service_b.tls.server_name) ou o host do endereço, que pode
+// ser uma URL ("https://service-b:8081/weather") ou host:porta ("service-b:50051")
+func serverName(configured, target string) string {
+	if configured != "" {
+		return configured
+	}
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		return host
+	}
+	return target
+}
+
+// peerTransport registra no span da chamada HTTP o certificado do Serviço B
+// Fica dentro do transporte do otelhttp, cujo span já está no contexto
+type peerTransport struct {
+	next http.RoundTripper
+}
+
+func (t peerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		recordPeer(req.Context(), resp.TLS)
+	}
+	return resp, err
+}
+
+// peerInterceptor registra o certificado do Serviço B nas chamadas gRPC
+// O span do otelgrpc é criado depois dos interceptors; o atributo fica no
+// span do Serviço A que originou a chamada
+func peerInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var p peer.Peer
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		recordPeer(ctx, &info.State)
+	}
+	return err
+}
+
+// recordPeer adiciona o subject do certificado do Serviço B ao span atual
+func recordPeer(ctx context.Context, cs *tls.ConnectionState) {
+	if subject := certs.PeerSubject(cs); subject != "" {
+		trace.SpanFromContext(ctx).SetAttributes(peerSubjectKey.String(subject))
+	}
+}
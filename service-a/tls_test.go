@@ -0,0 +1,134 @@
+package main
+
+import (
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/certs"
+	"cep-weather/internal/certs/certstest"
+	"crypto/tls"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// mutualTLS gera a CA e os certificados do Serviço B (servidor) e do Serviço A (cliente)
+func mutualTLS(t *testing.T) (server, client *certs.Reloader) {
+	t.Helper()
+	dir := t.TempDir()
+	ca := certstest.NewCA(t, "test-ca")
+	caFile := ca.WriteCA(t, dir)
+	serverCert, serverKey := ca.Issue(t, "service-b", "service-b").Write(t, dir, "service-b")
+	clientCert, clientKey := ca.Issue(t, "service-a").Write(t, dir, "service-a")
+
+	server, err := certs.NewReloader(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = certs.NewReloader(clientCert, clientKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, client
+}
+
+// peerSubject retorna o atributo tls.server.subject registrado em algum span
+func peerSubject(sr *tracetest.SpanRecorder) string {
+	for _, s := range sr.Ended() {
+		for _, kv := range s.Attributes() {
+			if kv.Key == peerSubjectKey {
+				return kv.Value.AsString()
+			}
+		}
+	}
+	return ""
+}
+
+func TestProxy_MutualTLS(t *testing.T) {
+	sr := setupTracer(t)
+	server, client := mutualTLS(t)
+
+	// httptest.Server.StartTLS incluiria o próprio certificado; o servidor
+	// usa o listener TLS com a configuração do Reloader
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clientSubject string
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientSubject = certs.PeerSubject(r.TLS)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"city":"Linhares","temp_C":28.5,"temp_F":83.3,"temp_K":301.5}`))
+		}),
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	go srv.Serve(tls.NewListener(lis, server.ServerConfig()))
+	defer srv.Close()
+
+	p := newProxy("https://"+lis.Addr().String(), time.Second, time.Second)
+	p.useTLS(client.ClientConfig("service-b"))
+	rec := doRequest(p, `{"cep":"29902-555"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if clientSubject != "CN=service-a,O=cep-weather" {
+		t.Fatalf("expected client certificate, got %q", clientSubject)
+	}
+	if got := peerSubject(sr); got != "CN=service-b,O=cep-weather" {
+		t.Fatalf("expected server subject on span, got %q", got)
+	}
+
+	// Sem o certificado do cliente, o Serviço B recusa a conexão
+	p.useTLS(&tls.Config{InsecureSkipVerify: true})
+	if rec := doRequest(p, `{"cep":"29902-555"}`); rec.Code == http.StatusOK {
+		t.Fatalf("expected handshake failure without client certificate")
+	}
+}
+
+func TestGRPCProxy_MutualTLS(t *testing.T) {
+	sr := setupTracer(t)
+	server, client := mutualTLS(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(server.ServerConfig())))
+	weatherpb.RegisterWeatherServiceServer(s, &fakeWeatherService{traceID: make(chan trace.TraceID, 1)})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	p, err := newGRPCProxy(lis.Addr().String(), time.Second, time.Second, client.ClientConfig("service-b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := doRequest(p, `{"cep":"29902-555"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := peerSubject(sr); got != "CN=service-b,O=cep-weather" {
+		t.Fatalf("expected server subject on span, got %q", got)
+	}
+}
+
+func TestServerName(t *testing.T) {
+	cases := []struct{ configured, target, want string }{
+		{"", "https://service-b:8081/weather", "service-b"},
+		{"", "https://127.0.0.1:8081/weather", "127.0.0.1"},
+		{"", "service-b:50051", "service-b"},
+		{"", "[::1]:50051", "::1"},
+		{"weather.internal", "https://10.0.0.5:8081/weather", "weather.internal"},
+	}
+	for _, tc := range cases {
+		if got := serverName(tc.configured, tc.target); got != tc.want {
+			t.Errorf("serverName(%q, %q) = %q, want %q", tc.configured, tc.target, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/problem"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newAirQualityHandler cria o handler do endpoint POST /air-quality
+//
+// Recebe o CEP ({"cep": "29902555"}) e devolve a concentração de PM2.5, PM10
+// e O3, o índice de qualidade do ar da US EPA com a categoria correspondente
+// e o índice UV.
+func newAirQualityHandler(rs *resolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("service-b")
+		ctx, span := tracer.Start(r.Context(), "process-air-quality-request")
+		defer span.End()
+
+		if r.Method != http.MethodPost {
+			span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
+			w.Header().Set("Allow", http.MethodPost)
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported")
+			return
+		}
+
+		var req api.AirQualityRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidZipcode, "request body must be a JSON object with a cep field")
+			return
+		}
+
+		c, err := cep.Parse(req.CEP)
+		if err != nil {
+			span.RecordError(fmt.Errorf("CEP inválido %q: %w", req.CEP, err))
+			problem.Write(w, r, problem.InvalidZipcode, err.Error())
+			return
+		}
+		span.SetAttributes(
+			attribute.String("cep.uf", c.State()),
+			attribute.String("cep.region", string(c.Region())),
+		)
+
+		resp, err := rs.airQuality(ctx, c)
+		if err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, kindFor(err), "")
+			return
+		}
+		span.SetAttributes(attribute.String("air_quality.category", resp.Category))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/weather"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAirQualityHandler(t *testing.T) {
+	_, weatherCalls := fakeUpstreams(t)
+	h := newAirQualityHandler(newResolver(time.Minute, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/air-quality", strings.NewReader(`{"cep":"01310-000"}`)))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp api.AirQualityResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		want := api.AirQualityResponse{City: "Cidade A", PM25: 8.2, PM10: 12.3, O3: 56.1, Index: 2, Category: api.AirModerate, UV: 5}
+		if resp != want {
+			t.Fatalf("expected %+v, got %+v", want, resp)
+		}
+	}
+	// A segunda consulta da mesma cidade vem do cache
+	if n := weatherCalls.Load(); n != 1 {
+		t.Errorf("expected 1 WeatherAPI call, got %d", n)
+	}
+}
+
+func TestAirQualityHandler_Fallback(t *testing.T) {
+	fakeUpstreams(t)
+
+	// A WeatherAPI falha e a Open-Meteo, próxima da lista, responde
+	openMeteo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			fmt.Fprint(w, `{"results":[{"latitude":-23.55,"longitude":-46.63}]}`)
+		case "/air-quality":
+			fmt.Fprint(w, `{"current":{"pm2_5":30.4,"pm10":41,"ozone":20.5,"us_aqi":120,"uv_index":3}}`)
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(openMeteo.Close)
+	overrides := map[*string]string{
+		&weather.AirQualityURL:          openMeteo.URL + "/weatherapi?key=%s&q=%s",
+		&weather.GeocodingURL:           openMeteo.URL + "/search?name=%s",
+		&weather.OpenMeteoAirQualityURL: openMeteo.URL + "/air-quality?latitude=%.4f&longitude=%.4f",
+	}
+	for url, value := range overrides {
+		url, orig := url, *url
+		*url = value
+		t.Cleanup(func() { *url = orig })
+	}
+
+	lookup := func(rs *resolver) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		newAirQualityHandler(rs).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/air-quality", strings.NewReader(`{"cep":"01310-000"}`)))
+		return rec
+	}
+
+	rec := lookup(newResolver(time.Minute, time.Minute))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp api.AirQualityResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	want := api.AirQualityResponse{City: "Cidade A", PM25: 30.4, PM10: 41, O3: 20.5, Index: 3, Category: api.AirUnhealthySensitive, UV: 3}
+	if resp != want {
+		t.Fatalf("expected %+v, got %+v", want, resp)
+	}
+
+	// Sem outro provedor na lista, a falha da WeatherAPI chega ao cliente
+	rs := newResolver(time.Minute, time.Minute)
+	rs.setAirQualityProviders([]weather.AirQualityProvider{weather.WeatherAPIAirQuality})
+	if rec := lookup(rs); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAirQualityHandler_Errors(t *testing.T) {
+	fakeUpstreams(t)
+	h := newAirQualityHandler(newResolver(time.Minute, time.Minute))
+
+	cases := map[string]struct {
+		method, body string
+		status       int
+		code         string
+	}{
+		"method":      {http.MethodGet, "", http.StatusMethodNotAllowed, "method_not_allowed"},
+		"invalid cep": {http.MethodPost, `{"cep":"123"}`, http.StatusUnprocessableEntity, "invalid_zipcode"},
+		"not found":   {http.MethodPost, `{"cep":"99999999"}`, http.StatusNotFound, "zipcode_not_found"},
+	}
+	for name, tc := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, "/air-quality", strings.NewReader(tc.body)))
+		if rec.Code != tc.status || !strings.Contains(rec.Body.String(), `"code":"`+tc.code+`"`) {
+			t.Errorf("%s: expected %d %s, got %d: %s", name, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestAirQualityCategory(t *testing.T) {
+	cases := map[int]string{1: api.AirGood, 3: api.AirUnhealthySensitive, 6: api.AirHazardous, 0: api.AirUnknown, 7: api.AirUnknown}
+	for index, want := range cases {
+		if got := api.AirQualityCategory(index); got != want {
+			t.Errorf("AirQualityCategory(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
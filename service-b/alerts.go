@@ -0,0 +1,63 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/problem"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newAlertsHandler cria o handler do endpoint POST /alerts
+//
+// Recebe o CEP ({"cep": "29902555"}) e devolve os alertas meteorológicos
+// ativos para a cidade, com severidade, tipo do evento, vigência e descrição.
+// Uma lista vazia é uma resposta válida (200), não um erro.
+func newAlertsHandler(rs *resolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("service-b")
+		ctx, span := tracer.Start(r.Context(), "process-alerts-request")
+		defer span.End()
+
+		if r.Method != http.MethodPost {
+			span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
+			w.Header().Set("Allow", http.MethodPost)
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported")
+			return
+		}
+
+		var req api.AlertsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidZipcode, "request body must be a JSON object with a cep field")
+			return
+		}
+
+		c, err := cep.Parse(req.CEP)
+		if err != nil {
+			span.RecordError(fmt.Errorf("CEP inválido %q: %w", req.CEP, err))
+			problem.Write(w, r, problem.InvalidZipcode, err.Error())
+			return
+		}
+		span.SetAttributes(
+			attribute.String("cep.uf", c.State()),
+			attribute.String("cep.region", string(c.Region())),
+		)
+
+		resp, err := rs.activeAlerts(ctx, c)
+		if err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, kindFor(err), "")
+			return
+		}
+		span.SetAttributes(attribute.Int("alerts.count", len(resp.Alerts)))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
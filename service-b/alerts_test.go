@@ -0,0 +1,84 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertsHandler(t *testing.T) {
+	_, weatherCalls := fakeUpstreams(t)
+	h := newAlertsHandler(newResolver(time.Minute, time.Minute))
+
+	lookup := func(cep string) api.AlertsResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(`{"cep":"`+cep+`"}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp api.AlertsResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := lookup("01310-000")
+	want := api.Alert{
+		Event:       "Heavy Rain",
+		Severity:    "severe",
+		Headline:    "Chuvas intensas",
+		Start:       "2024-05-01T08:00:00-03:00",
+		End:         "2024-05-02T08:00:00-03:00",
+		Description: "Chuva entre 30 e 60 mm/h.",
+	}
+	if resp.City != "Cidade A" || len(resp.Alerts) != 1 || resp.Alerts[0] != want {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	// Outro CEP da mesma cidade usa o cache
+	lookup("20000-000")
+	if n := weatherCalls.Load(); n != 1 {
+		t.Errorf("expected 1 WeatherAPI call, got %d", n)
+	}
+}
+
+func TestAlertsHandler_Empty(t *testing.T) {
+	fakeUpstreams(t)
+	h := newAlertsHandler(newResolver(time.Minute, time.Minute))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(`{"cep":"29902555"}`)))
+
+	// Sem alertas ativos, a resposta é 200 com a lista vazia (e não null)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"alerts":[]`) {
+		t.Fatalf("expected 200 with empty list, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAlertsHandler_Errors(t *testing.T) {
+	fakeUpstreams(t)
+	h := newAlertsHandler(newResolver(time.Minute, time.Minute))
+
+	cases := map[string]struct {
+		method, body string
+		status       int
+		code         string
+	}{
+		"method":      {http.MethodGet, "", http.StatusMethodNotAllowed, "method_not_allowed"},
+		"invalid cep": {http.MethodPost, `{"cep":"123"}`, http.StatusUnprocessableEntity, "invalid_zipcode"},
+		"not found":   {http.MethodPost, `{"cep":"99999999"}`, http.StatusNotFound, "zipcode_not_found"},
+	}
+	for name, tc := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, "/alerts", strings.NewReader(tc.body)))
+		if rec.Code != tc.status || !strings.Contains(rec.Body.String(), `"code":"`+tc.code+`"`) {
+			t.Errorf("%s: expected %d %s, got %d: %s", name, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/problem"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newBatchHandler cria o handler do endpoint POST /weather/batch
+//
+// Recebe até api.MaxBatchSize CEPs e devolve um resultado por CEP, na mesma
+// ordem da requisição (ver resolver.batch)
+func newBatchHandler(rs *resolver, concurrency int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("service-b")
+		ctx, span := tracer.Start(r.Context(), "process-weather-batch")
+		defer span.End()
+
+		if r.Method != http.MethodPost {
+			span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
+			w.Header().Set("Allow", http.MethodPost)
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported")
+			return
+		}
+
+		var req api.BatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidBatch, "request body must be a JSON object with a ceps array")
+			return
+		}
+		if err := req.Validate(); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidBatch, err.Error())
+			return
+		}
+
+		results := rs.batch(ctx, req.CEPs, concurrency)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(api.BatchResponse{Results: results})
+	}
+}
+
+// batch consulta o clima de um lote de CEPs, comum às APIs HTTP e gRPC
+//
+// Devolve um resultado por CEP, na mesma ordem recebida. Falhas individuais
+// (CEP inválido, não encontrado, erro na API externa) aparecem no item
+// correspondente sem afetar os demais.
+//
+// Para reduzir chamadas às APIs externas:
+// - CEPs repetidos no lote são consultados uma única vez
+// - Cidades repetidas são deduplicadas pelo cache do resolver
+// - No máximo "concurrency" CEPs são consultados em paralelo
+//
+// Cada CEP consultado gera um span "batch-item" próprio, ligado (link) ao span
+// do lote presente no contexto, evitando traces gigantes com centenas de spans filhos.
+func (rs *resolver) batch(ctx context.Context, ceps []string, concurrency int) []api.BatchItem {
+	span := trace.SpanFromContext(ctx)
+	results := make([]api.BatchItem, len(ceps))
+
+	// Valida os CEPs e agrupa as posições de CEPs repetidos
+	// CEPs inválidos recebem o erro imediatamente, sem consulta externa
+	positions := make(map[cep.CEP][]int)
+	var unique []cep.CEP
+	for i, raw := range ceps {
+		results[i].CEP = raw
+		c, err := cep.Parse(raw)
+		if err != nil {
+			p := problem.New(ctx, problem.InvalidZipcode, err.Error())
+			results[i].Error = &p
+			continue
+		}
+		if _, seen := positions[c]; !seen {
+			unique = append(unique, c)
+		}
+		positions[c] = append(positions[c], i)
+	}
+
+	span.SetAttributes(
+		attribute.Int("batch.size", len(ceps)),
+		attribute.Int("batch.unique_ceps", len(unique)),
+		attribute.Int("batch.concurrency", concurrency),
+	)
+
+	// Consulta os CEPs únicos com concorrência limitada
+	// Cada goroutine escreve apenas nas posições do seu CEP, sem disputa
+	link := trace.LinkFromContext(ctx)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, c := range unique {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c cep.CEP) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item := rs.batchItem(ctx, link, c)
+			for _, i := range positions[c] {
+				results[i].Weather, results[i].Error = item.Weather, item.Error
+			}
+		}(c)
+	}
+	wg.Wait()
+	return results
+}
+
+// batchItem consulta o clima de um CEP do lote em um span próprio
+// O span é a raiz de um novo trace, ligado ao span do lote pelo link informado
+func (rs *resolver) batchItem(ctx context.Context, link trace.Link, c cep.CEP) api.BatchItem {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "batch-item",
+		trace.WithNewRoot(),
+		trace.WithLinks(link),
+		trace.WithAttributes(
+			attribute.String("cep", c.String()),
+			attribute.String("cep.uf", c.State()),
+		),
+	)
+	defer span.End()
+
+	resp, err := rs.weather(ctx, c, false)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		p := problem.New(ctx, kindFor(err), "")
+		return api.BatchItem{CEP: c.String(), Error: &p}
+	}
+	span.SetStatus(codes.Ok, "")
+	return api.BatchItem{CEP: c.String(), Weather: &resp}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/location"
+	"cep-weather/internal/weather"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeUpstreams substitui a ViaCEP e a WeatherAPI por servidores locais
+// Retorna os contadores de chamadas recebidas por cada um
+func fakeUpstreams(t *testing.T) (viacepCalls, weatherCalls *atomic.Int32) {
+	t.Helper()
+	viacepCalls, weatherCalls = new(atomic.Int32), new(atomic.Int32)
+
+	// CEPs terminados em 000 pertencem a "Cidade A"; 99999999 não existe
+	viacep := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		viacepCalls.Add(1)
+		switch {
+		case strings.Contains(r.URL.Path, "99999999"):
+			fmt.Fprint(w, `{"erro": true}`)
+		case strings.Contains(r.URL.Path, "000/"):
+			fmt.Fprint(w, `{"localidade": "Cidade A"}`)
+		default:
+			fmt.Fprint(w, `{"localidade": "Cidade B"}`)
+		}
+	}))
+	t.Cleanup(viacep.Close)
+
+	// A previsão repete o mesmo dia (mín. 10 °C, máx. 20 °C) na quantidade pedida
+	weatherAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		weatherCalls.Add(1)
+		switch r.URL.Path {
+		case "/forecast":
+			days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+			day := `{"date":"2024-05-01","day":{"mintemp_c":10,"maxtemp_c":20,"daily_chance_of_rain":80,"totalprecip_mm":4.5}}`
+			fmt.Fprintf(w, `{"forecast":{"forecastday":[%s]}}`, strings.TrimSuffix(strings.Repeat(day+",", days), ","))
+		case "/alerts":
+			// Apenas a "Cidade A" possui alertas ativos
+			if r.URL.Query().Get("q") == "Cidade A" {
+				fmt.Fprint(w, `{"alerts":{"alert":[{"headline":"Chuvas intensas","severity":"Severe","event":"Heavy Rain","effective":"2024-05-01T08:00:00-03:00","expires":"2024-05-02T08:00:00-03:00","desc":"Chuva entre 30 e 60 mm/h."}]}}`)
+			} else {
+				fmt.Fprint(w, `{"alerts":{"alert":[]}}`)
+			}
+		case "/history":
+			dt := r.URL.Query().Get("dt")
+			fmt.Fprintf(w, `{"location":{"tz_id":"America/Sao_Paulo"},"forecast":{"forecastday":[{"date":%q,"day":{"mintemp_c":10,"maxtemp_c":20,"avgtemp_c":15},"hour":[{"time":"%s 22:00","temp_c":12},{"time":"%s 23:00","temp_c":11}]}]}}`, dt, dt, dt)
+		case "/air-quality":
+			fmt.Fprint(w, `{"current":{"uv":5,"air_quality":{"pm2_5":8.2,"pm10":12.3,"o3":56.1,"us-epa-index":2}}}`)
+		default:
+			fmt.Fprint(w, `{"current":{"temp_c":20,"feelslike_c":22,"humidity":65,"wind_kph":36,"wind_dir":"NE","uv":5,"pressure_mb":1013.25,"condition":{"text":"Sunny"}}}`)
+		}
+	}))
+	t.Cleanup(weatherAPI.Close)
+
+	// Aponta as URLs das APIs externas para os servidores falsos
+	overrides := map[*string]string{
+		&location.BaseURL:      viacep.URL + "/%s/json/",
+		&weather.ApiURL:        weatherAPI.URL + "/?key=%s&q=%s",
+		&weather.ForecastURL:   weatherAPI.URL + "/forecast?key=%s&q=%s&days=%d",
+		&weather.AirQualityURL: weatherAPI.URL + "/air-quality?key=%s&q=%s",
+		&weather.AlertsURL:     weatherAPI.URL + "/alerts?key=%s&q=%s",
+		&weather.HistoryURL:    weatherAPI.URL + "/history?key=%s&q=%s&dt=%s",
+	}
+	for url, value := range overrides {
+		url, orig := url, *url
+		*url = value
+		t.Cleanup(func() { *url = orig })
+	}
+	weather.SetAPIKey("testkey")
+	t.Cleanup(func() { weather.SetAPIKey("") })
+
+	return viacepCalls, weatherCalls
+}
+
+func TestBatchHandler(t *testing.T) {
+	viacepCalls, weatherCalls := fakeUpstreams(t)
+	h := newBatchHandler(newResolver(time.Minute, time.Minute), 4)
+
+	body := `{"ceps":["01310-000","01310000","20000-000","29902555","123","99999999"]}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp api.BatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(resp.Results))
+	}
+
+	// Resultados na mesma ordem da requisição, com o CEP como informado
+	expected := []struct {
+		cep, city, code string
+	}{
+		{"01310-000", "Cidade A", ""},
+		{"01310000", "Cidade A", ""},
+		{"20000-000", "Cidade A", ""},
+		{"29902555", "Cidade B", ""},
+		{"123", "", "invalid_zipcode"},
+		{"99999999", "", "zipcode_not_found"},
+	}
+	for i, e := range expected {
+		item := resp.Results[i]
+		if item.CEP != e.cep {
+			t.Errorf("item %d: expected cep %s, got %s", i, e.cep, item.CEP)
+		}
+		if e.code != "" {
+			if item.Error == nil || item.Error.Code != e.code {
+				t.Errorf("item %d: expected error %s, got %+v", i, e.code, item.Error)
+			}
+			continue
+		}
+		if item.Weather == nil || item.Weather.City != e.city {
+			t.Errorf("item %d: expected city %s, got %+v", i, e.city, item.Weather)
+		}
+	}
+
+	// CEPs repetidos (01310-000 e 01310000) são consultados uma única vez,
+	// e cidades repetidas geram uma única consulta de temperatura
+	if got := viacepCalls.Load(); got != 4 {
+		t.Errorf("expected 4 ViaCEP calls, got %d", got)
+	}
+	if got := weatherCalls.Load(); got != 2 {
+		t.Errorf("expected 2 WeatherAPI calls, got %d", got)
+	}
+}
+
+func TestBatchHandler_InvalidBatch(t *testing.T) {
+	h := newBatchHandler(newResolver(time.Minute, time.Minute), 4)
+
+	tooMany := make([]string, api.MaxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = "01310100"
+	}
+	large, _ := json.Marshal(api.BatchRequest{CEPs: tooMany})
+
+	for _, body := range []string{`{"ceps":[]}`, `not json`, string(large)} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/weather/batch", strings.NewReader(body)))
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected 422, got %d", rec.Code)
+		}
+	}
+}
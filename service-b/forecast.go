@@ -0,0 +1,71 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/problem"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newForecastHandler cria o handler do endpoint POST /forecast
+//
+// Recebe o CEP e a quantidade de dias ({"cep": "29902555", "days": 3}) e
+// devolve, para cada dia, as temperaturas mínima e máxima (em Celsius,
+// Fahrenheit e Kelvin) e a probabilidade de chuva.
+func newForecastHandler(rs *resolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("service-b")
+		ctx, span := tracer.Start(r.Context(), "process-forecast-request")
+		defer span.End()
+
+		if r.Method != http.MethodPost {
+			span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
+			w.Header().Set("Allow", http.MethodPost)
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported")
+			return
+		}
+
+		var req api.ForecastRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidZipcode, "request body must be a JSON object with a cep field")
+			return
+		}
+		if err := req.Validate(); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidDays, err.Error())
+			return
+		}
+		if req.Days == 0 {
+			req.Days = api.DefaultForecastDays
+		}
+
+		c, err := cep.Parse(req.CEP)
+		if err != nil {
+			span.RecordError(fmt.Errorf("CEP inválido %q: %w", req.CEP, err))
+			problem.Write(w, r, problem.InvalidZipcode, err.Error())
+			return
+		}
+		span.SetAttributes(
+			attribute.String("cep.uf", c.State()),
+			attribute.String("cep.region", string(c.Region())),
+			attribute.Int("forecast.days", req.Days),
+		)
+
+		resp, err := rs.forecast(ctx, c, req.Days)
+		if err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, kindFor(err), "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
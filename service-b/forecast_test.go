@@ -0,0 +1,74 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForecastHandler(t *testing.T) {
+	fakeUpstreams(t)
+	h := newForecastHandler(newResolver(time.Minute, time.Minute))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/forecast", strings.NewReader(`{"cep":"01310-000","days":2}`)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp api.ForecastResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.City != "Cidade A" || len(resp.Days) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	// Mínima e máxima recebem as mesmas conversões da resposta de clima atual
+	day := resp.Days[0]
+	if day.Min != (api.Temperature{C: 10, F: 50, K: 283.15}) || day.Max != (api.Temperature{C: 20, F: 68, K: 293.15}) {
+		t.Errorf("unexpected temperatures: %+v / %+v", day.Min, day.Max)
+	}
+	if day.ChanceOfRain != 80 || day.PrecipMM != 4.5 || day.Date != "2024-05-01" {
+		t.Errorf("unexpected day: %+v", day)
+	}
+}
+
+func TestForecastHandler_DefaultDays(t *testing.T) {
+	fakeUpstreams(t)
+	h := newForecastHandler(newResolver(time.Minute, time.Minute))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/forecast", strings.NewReader(`{"cep":"01310000"}`)))
+
+	var resp api.ForecastResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if len(resp.Days) != api.DefaultForecastDays {
+		t.Fatalf("expected %d days, got %d", api.DefaultForecastDays, len(resp.Days))
+	}
+}
+
+func TestForecastHandler_Errors(t *testing.T) {
+	fakeUpstreams(t)
+	h := newForecastHandler(newResolver(time.Minute, time.Minute))
+
+	cases := map[string]struct {
+		body   string
+		status int
+		code   string
+	}{
+		"too many days": {`{"cep":"01310000","days":15}`, http.StatusUnprocessableEntity, "invalid_days"},
+		"invalid cep":   {`{"cep":"123","days":3}`, http.StatusUnprocessableEntity, "invalid_zipcode"},
+		"not found":     {`{"cep":"99999999","days":3}`, http.StatusNotFound, "zipcode_not_found"},
+	}
+	for name, tc := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/forecast", strings.NewReader(tc.body)))
+		if rec.Code != tc.status || !strings.Contains(rec.Body.String(), `"code":"`+tc.code+`"`) {
+			t.Errorf("%s: expected %d %s, got %d: %s", name, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/i18n"
+	"cep-weather/internal/problem"
+	"cep-weather/internal/telemetry"
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// weatherServer implementa a API gRPC do Serviço B (weatherpb.WeatherService)
+// Usa o mesmo resolver da API HTTP, compartilhando cache e deduplicação
+type weatherServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	rs          *resolver // Pipeline de consulta (ViaCEP + WeatherAPI)
+	concurrency int       // Quantidade máxima de CEPs consultados em paralelo nos lotes
+}
+
+// newGRPCServer cria o servidor gRPC do Serviço B, instrumentado com OpenTelemetry
+// O contexto de rastreamento recebido nos metadados da chamada (W3C Trace
+// Context) é extraído, dando continuidade ao trace iniciado no Serviço A
+// Com tlsConfig (ver certs.Reloader.ServerConfig), as conexões usam TLS;
+// nil mantém o servidor sem TLS
+func newGRPCServer(rs *resolver, concurrency int, tlsConfig *tls.Config) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithPropagators(telemetry.Propagator()),
+		)),
+		grpc.ChainUnaryInterceptor(peerInterceptor, langInterceptor),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s := grpc.NewServer(opts...)
+	weatherpb.RegisterWeatherServiceServer(s, &weatherServer{rs: rs, concurrency: concurrency})
+	return s
+}
+
+// langInterceptor negocia o idioma das mensagens a partir do metadado
+// accept-language, equivalente gRPC do i18n.Middleware da API HTTP
+func langInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	lang := i18n.Default
+	if values := metadata.ValueFromIncomingContext(ctx, "accept-language"); len(values) > 0 {
+		lang = i18n.Negotiate(values[0])
+	}
+	return handler(i18n.WithLang(ctx, lang), req)
+}
+
+// GetWeather consulta o clima de um CEP
+// Com extended, a resposta inclui as condições detalhadas (ver resolver.weather)
+// Os erros seguem as mesmas categorias da API HTTP, convertidas em códigos gRPC
+// (ver api.GRPCCode)
+func (s *weatherServer) GetWeather(ctx context.Context, req *weatherpb.GetWeatherRequest) (*weatherpb.Weather, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "process-weather-request")
+	defer span.End()
+
+	c, err := cep.Parse(req.GetCep())
+	if err != nil {
+		span.RecordError(fmt.Errorf("CEP inválido %q: %w", req.GetCep(), err))
+		return nil, status.Error(api.GRPCCode(problem.InvalidZipcode), err.Error())
+	}
+	span.SetAttributes(
+		attribute.String("cep.uf", c.State()),
+		attribute.String("cep.region", string(c.Region())),
+		attribute.Bool("cep.capital", c.IsCapital()),
+	)
+
+	resp, err := s.rs.weather(ctx, c, req.GetExtended())
+	if err != nil {
+		span.RecordError(err)
+		kind := kindFor(err)
+		return nil, status.Error(api.GRPCCode(kind), kind.Title)
+	}
+	return resp.Proto(), nil
+}
+
+// BatchGetWeather consulta o clima de um lote de CEPs (ver resolver.batch)
+func (s *weatherServer) BatchGetWeather(ctx context.Context, req *weatherpb.BatchGetWeatherRequest) (*weatherpb.BatchGetWeatherResponse, error) {
+	tracer := otel.Tracer("service-b")
+	ctx, span := tracer.Start(ctx, "process-weather-batch")
+	defer span.End()
+
+	if err := (api.BatchRequest{CEPs: req.GetCeps()}).Validate(); err != nil {
+		span.RecordError(err)
+		return nil, status.Error(api.GRPCCode(problem.InvalidBatch), err.Error())
+	}
+
+	results := s.rs.batch(ctx, req.GetCeps(), s.concurrency)
+	resp := &weatherpb.BatchGetWeatherResponse{Results: make([]*weatherpb.BatchItem, len(results))}
+	for i, item := range results {
+		resp.Results[i] = item.Proto()
+	}
+	return resp, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"cep-weather/internal/api/weatherpb"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// newGRPCClient inicia o servidor gRPC do Serviço B em uma porta local e retorna um cliente
+func newGRPCClient(t *testing.T) weatherpb.WeatherServiceClient {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newGRPCServer(newResolver(time.Minute, time.Minute), 4, nil)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return weatherpb.NewWeatherServiceClient(conn)
+}
+
+func TestGRPC_GetWeather(t *testing.T) {
+	fakeUpstreams(t)
+	client := newGRPCClient(t)
+
+	resp, err := client.GetWeather(context.Background(), &weatherpb.GetWeatherRequest{Cep: "01310-000"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.GetCity() != "Cidade A" || resp.GetTempC() != 20 || resp.GetTempF() != 68 || resp.GetTempK() != 293.15 {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+
+	cases := map[string]codes.Code{
+		"123":      codes.InvalidArgument,
+		"99999999": codes.NotFound,
+	}
+	for cep, want := range cases {
+		_, err := client.GetWeather(context.Background(), &weatherpb.GetWeatherRequest{Cep: cep})
+		if got := status.Code(err); got != want {
+			t.Errorf("%s: expected %s, got %s", cep, want, got)
+		}
+	}
+}
+
+func TestGRPC_GetWeatherExtended(t *testing.T) {
+	fakeUpstreams(t)
+	client := newGRPCClient(t)
+
+	// Sem extended, a resposta não traz as condições detalhadas
+	resp, err := client.GetWeather(context.Background(), &weatherpb.GetWeatherRequest{Cep: "01310-000"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.GetConditions() != nil {
+		t.Fatalf("expected no conditions, got %v", resp.GetConditions())
+	}
+
+	resp, err = client.GetWeather(context.Background(), &weatherpb.GetWeatherRequest{Cep: "01310-000", Extended: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	c := resp.GetConditions()
+	if c.GetFeelsLike().GetTempC() != 22 || c.GetHumidity() != 65 || c.GetWind().GetKph() != 36 ||
+		c.GetWind().GetDirection() != "NE" || c.GetPressure().GetHpa() != 1013.25 || c.GetUv() != 5 || c.GetText() != "Sunny" {
+		t.Fatalf("unexpected conditions: %v", c)
+	}
+}
+
+func TestGRPC_BatchGetWeather(t *testing.T) {
+	fakeUpstreams(t)
+	client := newGRPCClient(t)
+
+	resp, err := client.BatchGetWeather(context.Background(), &weatherpb.BatchGetWeatherRequest{
+		Ceps: []string{"01310-000", "123", "99999999"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	results := resp.GetResults()
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].GetWeather().GetCity() != "Cidade A" {
+		t.Errorf("unexpected result 0: %v", results[0])
+	}
+	if results[1].GetError().GetCode() != "invalid_zipcode" {
+		t.Errorf("expected invalid_zipcode, got %v", results[1])
+	}
+	if results[2].GetError().GetCode() != "zipcode_not_found" {
+		t.Errorf("expected zipcode_not_found, got %v", results[2])
+	}
+
+	_, err = client.BatchGetWeather(context.Background(), &weatherpb.BatchGetWeatherRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for empty batch, got %v", err)
+	}
+}
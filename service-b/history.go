@@ -0,0 +1,79 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/problem"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newHistoryHandler cria o handler do endpoint GET /weather/history?cep=...&date=...
+//
+// Devolve as temperaturas mínima, máxima e média registradas no dia informado
+// para a cidade do CEP. A data é interpretada no fuso horário da localização
+// (ver api.ParseHistoryDate); quando informada como instante RFC 3339, a
+// resposta inclui também a temperatura da hora local correspondente.
+//
+// O parâmetro now permite fixar o relógio nos testes.
+func newHistoryHandler(rs *resolver, now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracer := otel.Tracer("service-b")
+		ctx, span := tracer.Start(r.Context(), "process-history-request")
+		defer span.End()
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
+			w.Header().Set("Allow", "GET, HEAD")
+			problem.Write(w, r, problem.MethodNotAllowed, "only GET is supported")
+			return
+		}
+
+		query := r.URL.Query()
+		c, err := cep.Parse(query.Get("cep"))
+		if err != nil {
+			span.RecordError(fmt.Errorf("CEP inválido %q: %w", query.Get("cep"), err))
+			problem.Write(w, r, problem.InvalidZipcode, err.Error())
+			return
+		}
+
+		// O fuso horário vem da UF do CEP e define o dia local consultado
+		loc, err := time.LoadLocation(c.TimeZone())
+		if err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.Internal, "")
+			return
+		}
+		span.SetAttributes(
+			attribute.String("cep.uf", c.State()),
+			attribute.String("cep.region", string(c.Region())),
+			attribute.String("history.timezone", loc.String()),
+		)
+
+		date, at, err := api.ParseHistoryDate(query.Get("date"), loc, now())
+		if err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidDate, err.Error())
+			return
+		}
+		span.SetAttributes(attribute.String("history.date", date))
+
+		resp, err := rs.pastWeather(ctx, c, date, at, loc)
+		if err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, kindFor(err), "")
+			return
+		}
+
+		// Dias passados não mudam: a resposta pode ser mantida em cache pelos clientes
+		w.Header().Set("Cache-Control", "public, max-age=86400, immutable")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// historyNow fixa o relógio em 2024-05-10 01:00 no horário de Brasília
+func historyNow() time.Time {
+	return time.Date(2024, 5, 10, 4, 0, 0, 0, time.UTC)
+}
+
+func TestHistoryHandler(t *testing.T) {
+	_, weatherCalls := fakeUpstreams(t)
+	h := newHistoryHandler(newResolver(time.Minute, time.Minute), historyNow)
+
+	lookup := func(query string) (*httptest.ResponseRecorder, api.HistoryResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/weather/history?"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp api.HistoryResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return rec, resp
+	}
+
+	rec, resp := lookup("cep=01310-000&date=2024-05-01")
+	if resp.City != "Cidade A" || resp.Date != "2024-05-01" || resp.TimeZone != "America/Sao_Paulo" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Min != (api.Temperature{C: 10, F: 50, K: 283.15}) || resp.Avg.C != 15 || resp.Max.C != 20 || resp.At != nil {
+		t.Errorf("unexpected temperatures: %+v", resp)
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "immutable") {
+		t.Errorf("expected immutable Cache-Control, got %q", cc)
+	}
+
+	// 02:30 UTC de 02/05 corresponde a 23:30 de 01/05 em São Paulo: mesmo dia, já em cache
+	_, resp = lookup("cep=01310000&date=2024-05-02T02:30:00Z")
+	if resp.Date != "2024-05-01" {
+		t.Fatalf("expected date converted to the location's time zone, got %s", resp.Date)
+	}
+	if resp.At == nil || resp.At.Time != "2024-05-01T23:00:00-03:00" || resp.At.C != 11 {
+		t.Errorf("unexpected hourly temperature: %+v", resp.At)
+	}
+	if n := weatherCalls.Load(); n != 1 {
+		t.Errorf("expected 1 WeatherAPI call, got %d", n)
+	}
+}
+
+func TestHistoryHandler_MaxEntries(t *testing.T) {
+	_, weatherCalls := fakeUpstreams(t)
+	rs := newResolver(time.Minute, time.Minute)
+	rs.setMaxEntries(0, 1)
+	h := newHistoryHandler(rs, historyNow)
+
+	// Com limite de um dia, consultar outro dia descarta o anterior do cache
+	for _, date := range []string{"2024-05-01", "2024-05-02", "2024-05-01"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/weather/history?cep=01310000&date="+date, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	if n := weatherCalls.Load(); n != 3 {
+		t.Errorf("expected evicted day to be fetched again (3 WeatherAPI calls), got %d", n)
+	}
+	if n := rs.history.Len(); n != 1 {
+		t.Errorf("expected history cache bounded to 1 entry, got %d", n)
+	}
+}
+
+func TestHistoryHandler_Errors(t *testing.T) {
+	fakeUpstreams(t)
+	h := newHistoryHandler(newResolver(time.Minute, time.Minute), historyNow)
+
+	cases := map[string]struct {
+		method, query string
+		status        int
+		code          string
+	}{
+		"method":       {http.MethodPost, "cep=01310000&date=2024-05-01", http.StatusMethodNotAllowed, "method_not_allowed"},
+		"invalid cep":  {http.MethodGet, "cep=123&date=2024-05-01", http.StatusUnprocessableEntity, "invalid_zipcode"},
+		"missing date": {http.MethodGet, "cep=01310000", http.StatusUnprocessableEntity, "invalid_date"},
+		"bad format":   {http.MethodGet, "cep=01310000&date=01/05/2024", http.StatusUnprocessableEntity, "invalid_date"},
+		"today":        {http.MethodGet, "cep=01310000&date=2024-05-10", http.StatusUnprocessableEntity, "invalid_date"},
+		"not found":    {http.MethodGet, "cep=99999999&date=2024-05-01", http.StatusNotFound, "zipcode_not_found"},
+	}
+	for name, tc := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, "/weather/history?"+tc.query, nil))
+		if rec.Code != tc.status || !strings.Contains(rec.Body.String(), `"code":"`+tc.code+`"`) {
+			t.Errorf("%s: expected %d %s, got %d: %s", name, tc.status, tc.code, rec.Code, rec.Body.String())
+		}
+	}
+}
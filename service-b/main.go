@@ -9,124 +9,116 @@ package main
 
 // Importação das dependências necessárias
 import (
-	"cep-weather/internal/location"  // Pacote para consulta de CEP via ViaCEP
+	"cep-weather/internal/api"       // Pacote com o contrato compartilhado entre os serviços
+	"cep-weather/internal/cep"       // Pacote para interpretação e validação de CEP
+	"cep-weather/internal/certs"     // Pacote para os certificados TLS (com recarga)
+	"cep-weather/internal/config"    // Pacote para carga e validação da configuração
+	"cep-weather/internal/i18n"      // Pacote para localização das mensagens (Accept-Language)
+	"cep-weather/internal/problem"   // Pacote para respostas de erro padronizadas (RFC 7807)
 	"cep-weather/internal/telemetry" // Pacote para configuração de telemetria OpenTelemetry
-	"cep-weather/internal/weather"   // Pacote para consulta de temperatura via WeatherAPI
+	"cep-weather/internal/units"     // Pacote para conversões de unidade (temperatura, vento, pressão)
+	"cep-weather/internal/weather"   // Pacote para consultas à WeatherAPI
 	"context"                        // Pacote para manipulação de contexto (rastreamento distribuído)
+	"crypto/tls"                     // Pacote para o TLS dos servidores HTTP e gRPC
 	"encoding/json"                  // Pacote para codificação/decodificação JSON
 	"fmt"                            // Pacote para formatação e impressão
+	"log"                            // Pacote para registro da configuração efetiva
+	"net"                            // Pacote para os listeners TCP dos servidores HTTP e gRPC
 	"net/http"                       // Pacote para servidor HTTP
 	"os"                             // Pacote para interação com o sistema operacional (variáveis de ambiente)
-	"regexp"                         // Pacote para expressões regulares (validação de CEP)
+	"time"                           // Pacote para durações (tempos de expiração do cache)
+	_ "time/tzdata"                  // Base de fusos horários embutida (a imagem Alpine não a inclui)
 
 	// Pacotes do OpenTelemetry para rastreamento distribuído
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// WeatherResponse define a estrutura da resposta JSON do serviço
-// Formato de resposta conforme especificação dos requisitos
-type WeatherResponse struct {
-	City  string  `json:"city"`   // Nome da cidade encontrada via ViaCEP
-	TempC float64 `json:"temp_C"` // Temperatura em Celsius (da WeatherAPI)
-	TempF float64 `json:"temp_F"` // Temperatura em Fahrenheit (convertida: F = C * 1.8 + 32)
-	TempK float64 `json:"temp_K"` // Temperatura em Kelvin (convertida: K = C + 273)
-}
-
-// handler é a função que processa as requisições HTTP recebidas do Serviço A
+// newHandler cria a função que processa as requisições HTTP recebidas do Serviço A
 // Implementa toda a lógica de orquestração do Serviço B conforme requisitos:
 // - Validação de CEP
 // - Consulta à API ViaCEP (com span de rastreamento)
 // - Consulta à API WeatherAPI (com span de rastreamento)
 // - Conversão de temperaturas
 // - Retorno de resposta formatada
-func handler(w http.ResponseWriter, r *http.Request) {
-	// Inicializa o tracer do OpenTelemetry para criar spans de rastreamento
-	// O contexto do request já contém informações de rastreamento do Serviço A
-	tracer := otel.Tracer("service-b")
-	ctx := r.Context()
-	ctx, span := tracer.Start(ctx, "process-weather-request")
-	defer span.End() // Garante que o span será finalizado
-
-	// Validação: Verifica se o método HTTP é POST (conforme requisito)
-	if r.Method != http.MethodPost {
-		span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+func newHandler(rs *resolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Inicializa o tracer do OpenTelemetry para criar spans de rastreamento
+		// O contexto do request já contém informações de rastreamento do Serviço A
+		tracer := otel.Tracer("service-b")
+		ctx := r.Context()
+		ctx, span := tracer.Start(ctx, "process-weather-request")
+		defer span.End() // Garante que o span será finalizado
+
+		// Validação: Verifica se o método HTTP é POST (conforme requisito)
+		if r.Method != http.MethodPost {
+			span.RecordError(fmt.Errorf("método não permitido: %s", r.Method))
+			problem.Write(w, r, problem.MethodNotAllowed, "only POST is supported")
+			return
+		}
 
-	// Define a estrutura para receber o CEP do JSON
-	var input struct {
-		CEP string `json:"cep"`
-	}
+		// Define a estrutura para receber o CEP do JSON
+		var input api.WeatherRequest
 
-	// Decodifica o JSON do corpo da requisição
-	// Se falhar, retorna erro 422 conforme especificação
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		span.RecordError(err)
-		http.Error(w, "invalid zipcode", http.StatusUnprocessableEntity) // 422 conforme requisito
-		return
-	}
-
-	// Validação: Verifica se o CEP contém exatamente 8 dígitos numéricos
-	// Requisito: CEP deve ser uma string válida com 8 dígitos
-	if !regexp.MustCompile(`^\d{8}$`).MatchString(input.CEP) {
-		span.RecordError(fmt.Errorf("formato de CEP inválido: %s", input.CEP))
-		http.Error(w, "invalid zipcode", http.StatusUnprocessableEntity) // 422 conforme requisito
-		return
-	}
+		// Decodifica o JSON do corpo da requisição
+		// Se falhar, retorna erro 422 conforme especificação
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			span.RecordError(err)
+			problem.Write(w, r, problem.InvalidZipcode, "request body must be a JSON object with a cep field") // 422 conforme requisito
+			return
+		}
 
-	// Consulta a localização usando a API ViaCEP
-	// IMPORTANTE: A função GetLocationByCEP cria um span interno para medir
-	// o tempo de resposta da chamada externa à API ViaCEP
-	loc, err := location.GetLocationByCEP(ctx, input.CEP)
-	if err != nil {
-		span.RecordError(err)
-		// Requisito: Retorna 404 se CEP não for encontrado
-		if err.Error() == "zipcode not found" {
-			http.Error(w, "can not find zipcode", http.StatusNotFound) // 404 conforme requisito
+		// Validação: Interpreta e normaliza o CEP ("01310-100" -> "01310100")
+		// Requisito: CEP deve ser uma string válida com 8 dígitos
+		c, err := cep.Parse(input.CEP)
+		if err != nil {
+			span.RecordError(fmt.Errorf("CEP inválido %q: %w", input.CEP, err))
+			problem.Write(w, r, problem.InvalidZipcode, err.Error()) // 422 conforme requisito
 			return
 		}
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
 
-	// Consulta a temperatura usando a WeatherAPI
-	// IMPORTANTE: A função GetTemperature cria um span interno para medir
-	// o tempo de resposta da chamada externa à API WeatherAPI
-	tempC, err := weather.GetTemperature(ctx, loc.City)
-	if err != nil {
-		span.RecordError(err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+		// UF, região e capital são inferidas localmente a partir da tabela de faixas
+		// de CEP; CEPs fora das faixas já foram rejeitados sem consultar a ViaCEP
+		span.SetAttributes(
+			attribute.String("cep.uf", c.State()),
+			attribute.String("cep.region", string(c.Region())),
+			attribute.Bool("cep.capital", c.IsCapital()),
+		)
+
+		// Consulta a localização (ViaCEP) e a temperatura (WeatherAPI)
+		// IMPORTANTE: As funções de consulta criam spans internos para medir
+		// o tempo de resposta das chamadas externas; resultados ficam em cache
+		resp, err := rs.weather(ctx, c, input.Extended)
+		if err != nil {
+			span.RecordError(err)
+			// Requisito: Retorna 404 se CEP não for encontrado
+			problem.Write(w, r, kindFor(err), "")
+			return
+		}
 
-	// Calcula as conversões de temperatura conforme fórmulas especificadas
-	// Fahrenheit: F = C * 1.8 + 32
-	tempF := tempC*1.8 + 32
-	// Kelvin: K = C + 273
-	tempK := tempC + 273
-
-	// Monta a resposta no formato especificado nos requisitos
-	// Requisito: HTTP 200 com JSON contendo city, temp_C, temp_F, temp_K
-	resp := WeatherResponse{
-		City:  loc.City,
-		TempC: tempC,
-		TempF: tempF,
-		TempK: tempK,
+		// Define o cabeçalho e envia a resposta JSON ao cliente
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK) // 200 conforme requisito
+		json.NewEncoder(w).Encode(resp)
 	}
-
-	// Define o cabeçalho e envia a resposta JSON ao cliente
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK) // 200 conforme requisito
-	json.NewEncoder(w).Encode(resp)
 }
 
 // função principal - ponto de entrada da aplicação
 func main() {
+	// Carrega a configuração: valores padrão, arquivo opcional (CONFIG_FILE) e
+	// variáveis de ambiente. Valores inválidos (inclusive a ausência da chave
+	// da WeatherAPI) interrompem a inicialização
+	cfg := config.DefaultServiceB()
+	if err := config.Load(&cfg, os.Getenv(config.FileEnv)); err != nil {
+		fmt.Printf("Configuração inválida:\n%v\n", err)
+		os.Exit(1)
+	}
+	log.Printf("Configuração efetiva:\n%s", config.Format(cfg))
+
 	// Inicializa o OpenTelemetry com o nome do serviço
 	// Isso configura o sistema de rastreamento distribuído e conexão com Zipkin
-	tp, err := telemetry.InitTracer("service-b")
+	tp, err := telemetry.InitTracer("service-b", cfg.ZipkinURL)
 	if err != nil {
 		fmt.Printf("Erro ao inicializar o tracer: %v\n", err)
 		os.Exit(1)
@@ -139,22 +131,124 @@ func main() {
 		}
 	}()
 
-	// Configura a porta do servidor HTTP
-	// Permite configurar via variável de ambiente (útil para Docker)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8081" // Porta padrão para o Serviço B conforme requisitos
+	// Métricas do OpenTelemetry (ex: uso das chaves da WeatherAPI e métricas
+	// HTTP do otelhttp), expostas em GET /metrics no formato do Prometheus, na
+	// porta metrics.port
+	mp, metricsHandler, err := telemetry.InitMeter("service-b")
+	if err != nil {
+		fmt.Printf("Erro ao inicializar as métricas: %v\n", err)
+		os.Exit(1)
 	}
+	defer func() {
+		if err := mp.Shutdown(context.Background()); err != nil {
+			fmt.Printf("Erro ao desligar o provedor de métricas: %v\n", err)
+		}
+	}()
+	if cfg.Metrics.Enabled {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metricsHandler)
+			log.Printf("Métricas disponíveis em :%s/metrics", cfg.Metrics.Port)
+			if err := http.ListenAndServe(":"+cfg.Metrics.Port, mux); err != nil {
+				log.Printf("Erro ao iniciar o servidor de métricas: %v", err)
+			}
+		}()
+	}
+
+	// Define o formato das respostas de erro
+	// legacy_errors (LEGACY_ERRORS=true) mantém as mensagens em texto puro do contrato original
+	problem.Legacy = cfg.LegacyErrors
+
+	// Conversões de unidade
+	// legacy_kelvin mantém a fórmula do contrato original (K = C + 273)
+	// precision define as casas decimais das respostas (negativo desativa o arredondamento)
+	units.LegacyKelvin = cfg.Units.LegacyKelvin
+	units.Precision = cfg.Units.Precision
+
+	// Chaves da WeatherAPI (usadas conforme key_strategy) e proporção dos
+	// traces amostrados; ambas podem ser alteradas em execução (ver reloader)
+	weather.SetKeyPool(newKeyPool(cfg))
+	telemetry.SetSampleRatio(cfg.SamplerRatio)
+
+	// Formatos do contexto propagado (propagators, OTEL_PROPAGATORS), que
+	// devem incluir os usados pelo Serviço A. Os membros do baggage listados
+	// em baggage_attributes (ex: client.name) são copiados para os spans
+	if err := telemetry.SetPropagators(cfg.Propagators...); err != nil {
+		fmt.Printf("Erro ao configurar os propagadores: %v\n", err)
+		os.Exit(1)
+	}
+	tp.RegisterSpanProcessor(telemetry.NewBaggageSpanProcessor(cfg.BaggageAttributes...))
+
+	// Limites de taxa das chamadas à ViaCEP e à WeatherAPI (rate_limits)
+	setLimiters(cfg.RateLimits)
 
-	// Configura o handler HTTP com instrumentação OpenTelemetry
+	// Configura o pipeline de consulta com cache
+	rs := newResolver(time.Duration(cfg.Cache.LocationTTL), time.Duration(cfg.Cache.WeatherTTL))
+	rs.setMaxEntries(cfg.Cache.MaxEntries, cfg.Cache.HistoryMaxEntries)
+	providers, err := weather.AirQualityProviders(cfg.AirQualityProviders...)
+	if err != nil {
+		fmt.Printf("Erro ao configurar os provedores de qualidade do ar: %v\n", err)
+		os.Exit(1)
+	}
+	rs.setAirQualityProviders(providers)
+
+	// Recarrega a configuração quando o arquivo é alterado ou ao receber SIGHUP,
+	// permitindo rotacionar a chave e ajustar TTLs e amostragem sem reiniciar
+	newReloader(os.Getenv(config.FileEnv), cfg, rs).watch(context.Background())
+
+	// Configura os handlers HTTP com instrumentação OpenTelemetry
 	// O otelhttp.NewHandler automaticamente cria spans para cada requisição
 	// e propaga o contexto de rastreamento distribuído
-	handler := otelhttp.NewHandler(http.HandlerFunc(handler), "weather-handler")
-	http.Handle("/weather", handler) // Endpoint: POST /weather
+	http.Handle("/weather", otelhttp.NewHandler(tlsPeer(i18n.Middleware(newHandler(rs))), "weather-handler"))                                // Endpoint: POST /weather
+	http.Handle("/weather/batch", otelhttp.NewHandler(tlsPeer(i18n.Middleware(newBatchHandler(rs, cfg.BatchConcurrency))), "batch-handler")) // Endpoint: POST /weather/batch
+	http.Handle("/forecast", otelhttp.NewHandler(tlsPeer(i18n.Middleware(newForecastHandler(rs))), "forecast-handler"))                      // Endpoint: POST /forecast
+	http.Handle("/air-quality", otelhttp.NewHandler(tlsPeer(i18n.Middleware(newAirQualityHandler(rs))), "air-quality-handler"))              // Endpoint: POST /air-quality
+	http.Handle("/alerts", otelhttp.NewHandler(tlsPeer(i18n.Middleware(newAlertsHandler(rs))), "alerts-handler"))                            // Endpoint: POST /alerts
+	http.Handle("/weather/history", otelhttp.NewHandler(tlsPeer(i18n.Middleware(newHistoryHandler(rs, time.Now))), "history-handler"))       // Endpoint: GET /weather/history
+
+	// TLS dos servidores HTTP e gRPC (tls); com ca_file, apenas clientes com
+	// certificado emitido pela CA são aceitos (mTLS). Certificados renovados
+	// valem para as novas conexões sem reinício
+	var serverTLS *tls.Config
+	if cfg.TLS.Enabled {
+		reloader, err := certs.NewReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.CAFile)
+		if err != nil {
+			fmt.Printf("Erro ao carregar os certificados TLS: %v\n", err)
+			os.Exit(1)
+		}
+		if interval := time.Duration(cfg.TLS.ReloadInterval); interval > 0 {
+			reloader.Watch(context.Background(), interval)
+		}
+		serverTLS = reloader.ServerConfig()
+	}
+
+	// Inicia o servidor gRPC em paralelo ao HTTP, com os mesmos recursos
+	// Chamadores internos podem usar o WeatherService em vez dos endpoints HTTP
+	lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		fmt.Printf("Erro ao abrir a porta gRPC: %v\n", err)
+		os.Exit(1)
+	}
+	grpcServer := newGRPCServer(rs, cfg.BatchConcurrency, serverTLS)
+	go func() {
+		fmt.Printf("Serviço B (gRPC) rodando na porta %s...\n", cfg.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			fmt.Printf("Erro ao iniciar o servidor gRPC: %v\n", err)
+			os.Exit(1)
+		}
+	}()
 
 	// Inicia o servidor HTTP na porta configurada
-	fmt.Printf("Serviço B rodando na porta %s...\n", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	httpLis, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		fmt.Printf("Erro ao abrir a porta HTTP: %v\n", err)
+		os.Exit(1)
+	}
+	if serverTLS != nil {
+		httpLis = tls.NewListener(httpLis, serverTLS)
+	}
+	fmt.Printf("Serviço B rodando na porta %s...\n", cfg.Port)
+	if err := http.Serve(httpLis, nil); err != nil {
 		fmt.Printf("Erro ao iniciar o servidor: %v\n", err)
 		os.Exit(1)
 	}
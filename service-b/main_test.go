@@ -0,0 +1,106 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/i18n"
+	"cep-weather/internal/location"
+	"cep-weather/internal/ratelimit"
+	"cep-weather/internal/units"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_Extended(t *testing.T) {
+	fakeUpstreams(t)
+	h := newHandler(newResolver(time.Minute, time.Minute))
+
+	// Sem "extended", a resposta mantém o contrato original
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(`{"cep":"01310000"}`)))
+	if strings.Contains(rec.Body.String(), "conditions") {
+		t.Fatalf("expected no conditions by default, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(`{"cep":"01310000","extended":true}`)))
+	resp, err := api.DecodeWeatherResponse(rec.Body)
+	if err != nil {
+		t.Fatalf("expected valid response, got %v", err)
+	}
+	c := resp.Conditions
+	if c == nil {
+		t.Fatalf("expected conditions in extended response")
+	}
+	if c.FeelsLike != (api.Temperature{C: 22, F: 71.6, K: 295.15}) {
+		t.Errorf("unexpected feels_like: %+v", c.FeelsLike)
+	}
+	if c.Humidity != 65 || c.UV != 5 || c.Text != "Sunny" || c.Wind.Direction != "NE" {
+		t.Errorf("unexpected conditions: %+v", c)
+	}
+	// 36 km/h = 10 m/s ≈ 22.37 mph; valores arredondados para 2 casas
+	if c.Wind.Kph != 36 || c.Wind.MS != 10 || c.Wind.Mph != 22.37 {
+		t.Errorf("unexpected wind: %+v", c.Wind)
+	}
+	if c.Pressure != (api.Pressure{Hpa: 1013.25, InHg: 29.92}) {
+		t.Errorf("unexpected pressure: %+v", c.Pressure)
+	}
+}
+
+func TestHandler_LocalizedConditions(t *testing.T) {
+	fakeUpstreams(t)
+	h := i18n.Middleware(newHandler(newResolver(time.Minute, time.Minute)))
+
+	req := httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(`{"cep":"01310000","extended":true}`))
+	req.Header.Set("Accept-Language", "es")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	resp, err := api.DecodeWeatherResponse(rec.Body)
+	if err != nil {
+		t.Fatalf("expected valid response, got %v", err)
+	}
+	if resp.Conditions == nil || resp.Conditions.Text != "Soleado" {
+		t.Fatalf("expected localized condition text, got %+v", resp.Conditions)
+	}
+}
+
+func TestHandler_LegacyKelvin(t *testing.T) {
+	fakeUpstreams(t)
+	units.LegacyKelvin = true
+	defer func() { units.LegacyKelvin = false }()
+	h := newHandler(newResolver(time.Minute, time.Minute))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(`{"cep":"01310000"}`)))
+	resp, err := api.DecodeWeatherResponse(rec.Body)
+	if err != nil {
+		t.Fatalf("expected valid response, got %v", err)
+	}
+	if resp.TempK != 293 {
+		t.Fatalf("expected legacy 293 K, got %v", resp.TempK)
+	}
+}
+
+func TestHandler_RateLimited(t *testing.T) {
+	fakeUpstreams(t)
+	location.SetLimiter(ratelimit.New("viacep", 0.001, 1, ratelimit.FailFast))
+	defer location.SetLimiter(nil)
+	h := newHandler(newResolver(time.Minute, time.Minute))
+
+	// O primeiro CEP consome o único token; o segundo não obtém token a tempo
+	for _, tc := range []struct {
+		cep  string
+		want int
+	}{
+		{"01310000", http.StatusOK},
+		{"20040000", http.StatusServiceUnavailable},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(`{"cep":"`+tc.cep+`"}`)))
+		if rec.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.cep, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+}
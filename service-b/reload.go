@@ -0,0 +1,292 @@
+package main
+
+import (
+	"cep-weather/internal/config"
+	"cep-weather/internal/location"
+	"cep-weather/internal/ratelimit"
+	"cep-weather/internal/telemetry"
+	"cep-weather/internal/weather"
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// reloader aplica alterações da configuração sem reiniciar o Serviço B
+//
+// A recarga acontece quando o arquivo de configuração (CONFIG_FILE) é
+// alterado ou quando o processo recebe SIGHUP. Apenas as opções que podem ser
+// trocadas com segurança em execução são aplicadas:
+//   - weather_api_key, weather_api_keys, key_strategy e key_quarantine:
+//     rotação das chaves da WeatherAPI
+//   - sampler_ratio: proporção dos traces amostrados
+//   - rate_limits: limites de taxa das chamadas à ViaCEP e à WeatherAPI
+//   - cache: validade e tamanho dos caches
+//   - air_quality_providers: provedores de qualidade do ar e ordem de consulta
+//
+// As demais (portas, Zipkin, formato dos erros, unidades, paralelismo dos
+// lotes) exigem reinício: alterações nelas são registradas no log e ignoradas.
+//
+// Assim como na inicialização, as variáveis de ambiente têm precedência sobre
+// o arquivo. Como o ambiente do processo não muda, uma opção recarregável
+// definida por variável de ambiente fica fixa: a alteração no arquivo não tem
+// efeito. Essas opções são listadas no log e no span a cada recarga.
+//
+// Requisições em andamento não são afetadas: cada chamada à WeatherAPI lê a
+// chave uma única vez, a amostragem é decidida na criação do span e os novos
+// TTLs valem apenas para os resultados armazenados depois da recarga. Da mesma
+// forma, cada consulta de qualidade do ar usa a ordem de provedores em vigor
+// quando começou.
+type reloader struct {
+	path    string                          // Arquivo de configuração ("" usa apenas o ambiente)
+	rs      *resolver                       // Pipeline cujos caches recebem os novos TTLs
+	mu      sync.Mutex                      // Serializa recargas simultâneas (arquivo e SIGHUP)
+	current atomic.Pointer[config.ServiceB] // Configuração em vigor
+}
+
+// newReloader cria o reloader a partir da configuração carregada na inicialização
+func newReloader(path string, cfg config.ServiceB, rs *resolver) *reloader {
+	rl := &reloader{path: path, rs: rs}
+	rl.current.Store(&cfg)
+	return rl
+}
+
+// newKeyPool cria o pool de chaves da WeatherAPI a partir da configuração
+func newKeyPool(cfg config.ServiceB) *weather.KeyPool {
+	return weather.NewKeyPool(cfg.APIKeys(), weather.Strategy(cfg.KeyStrategy), time.Duration(cfg.KeyQuarantine))
+}
+
+// setLimiters define os limitadores de taxa das APIs externas
+func setLimiters(cfg config.RateLimits) {
+	location.SetLimiter(newLimiter("viacep", cfg.ViaCEP))
+	weather.SetLimiter(newLimiter("weatherapi", cfg.WeatherAPI))
+}
+
+// newLimiter cria o limitador de taxa de uma API externa
+func newLimiter(name string, cfg config.RateLimit) *ratelimit.Limiter {
+	return ratelimit.New(name, cfg.Rate, cfg.Burst, ratelimit.Mode(cfg.Mode))
+}
+
+// reload lê novamente a configuração e aplica as opções recarregáveis
+//
+// Uma configuração inválida é rejeitada por inteiro: a configuração em vigor
+// é mantida, evitando que um arquivo editado pela metade derrube o serviço.
+// Cada recarga gera o span "config-reload", com o evento "config.reloaded"
+// listando as opções alteradas, e uma linha no log.
+//
+// Parâmetros:
+//   - reason: Origem da recarga ("file" ou "sighup")
+//
+// Retorna erro quando a nova configuração é inválida
+func (rl *reloader) reload(reason string) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	_, span := otel.Tracer("service-b").Start(context.Background(), "config-reload",
+		trace.WithAttributes(attribute.String("config.reload.reason", reason)))
+	defer span.End()
+
+	next := config.DefaultServiceB()
+	if err := config.Load(&next, rl.path); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid configuration")
+		log.Printf("Recarga da configuração (%s) rejeitada; a configuração atual foi mantida:\n%v", reason, err)
+		return err
+	}
+
+	// Os nomes já foram validados por config.Load
+	providers, err := weather.AirQualityProviders(next.AirQualityProviders...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid configuration")
+		log.Printf("Recarga da configuração (%s) rejeitada; a configuração atual foi mantida:\n%v", reason, err)
+		return err
+	}
+
+	prev := *rl.current.Load()
+	changed := reloadableChanges(prev, next)
+	restart := restartRequired(prev, next)
+	shadowed := shadowedByEnv(next)
+
+	// Apenas as opções recarregáveis passam a valer; as demais permanecem
+	// como na inicialização até o próximo reinício
+	effective := prev
+	effective.WeatherAPIKey = next.WeatherAPIKey
+	effective.WeatherAPIKeys = next.WeatherAPIKeys
+	effective.KeyStrategy = next.KeyStrategy
+	effective.KeyQuarantine = next.KeyQuarantine
+	effective.SamplerRatio = next.SamplerRatio
+	effective.Cache = next.Cache
+	effective.RateLimits = next.RateLimits
+	effective.AirQualityProviders = next.AirQualityProviders
+
+	// O pool só é recriado quando as chaves mudam, preservando a contagem de
+	// uso e as quarentenas em andamento
+	if keysChanged(prev, next) {
+		weather.SetKeyPool(newKeyPool(effective))
+	}
+	// Os limitadores também só são recriados quando alterados, preservando
+	// os tokens consumidos e as chamadas na fila
+	if prev.RateLimits != next.RateLimits {
+		setLimiters(effective.RateLimits)
+	}
+	telemetry.SetSampleRatio(effective.SamplerRatio)
+	rl.rs.setTTLs(time.Duration(effective.Cache.LocationTTL), time.Duration(effective.Cache.WeatherTTL))
+	rl.rs.setMaxEntries(effective.Cache.MaxEntries, effective.Cache.HistoryMaxEntries)
+	rl.rs.setAirQualityProviders(providers)
+	rl.current.Store(&effective)
+
+	span.AddEvent("config.reloaded", trace.WithAttributes(
+		attribute.StringSlice("config.changed", changed),
+		attribute.StringSlice("config.restart_required", restart),
+		attribute.StringSlice("config.env_shadowed", shadowed),
+	))
+	if len(changed) == 0 {
+		log.Printf("Configuração recarregada (%s): nenhuma opção recarregável foi alterada", reason)
+	} else {
+		log.Printf("Configuração recarregada (%s): %s", reason, strings.Join(changed, ", "))
+	}
+	if len(restart) > 0 {
+		log.Printf("Alterações em %s exigem reinício do serviço e foram ignoradas", strings.Join(restart, ", "))
+	}
+	if len(shadowed) > 0 {
+		log.Printf("Opções recarregáveis definidas por variáveis de ambiente, que têm precedência sobre o arquivo (alterações no arquivo não têm efeito): %s", strings.Join(shadowed, ", "))
+	}
+	return nil
+}
+
+// reloadableOptions são as opções do arquivo aplicadas na recarga (ver reload)
+var reloadableOptions = []string{
+	"weather_api_key", "weather_api_keys", "key_strategy", "key_quarantine",
+	"sampler_ratio", "cache", "rate_limits", "air_quality_providers",
+}
+
+// shadowedByEnv lista as opções recarregáveis definidas por variáveis de
+// ambiente, no formato "opção (VARIÁVEL)", em ordem alfabética
+func shadowedByEnv(cfg config.ServiceB) []string {
+	var shadowed []string
+	for option, env := range config.EnvOverrides(cfg) {
+		for _, r := range reloadableOptions {
+			if option == r || strings.HasPrefix(option, r+".") {
+				shadowed = append(shadowed, option+" ("+env+")")
+				break
+			}
+		}
+	}
+	slices.Sort(shadowed)
+	return shadowed
+}
+
+// keysChanged informa se alguma opção do pool de chaves foi alterada
+func keysChanged(prev, next config.ServiceB) bool {
+	return prev.WeatherAPIKey != next.WeatherAPIKey ||
+		!slices.Equal(prev.WeatherAPIKeys, next.WeatherAPIKeys) ||
+		prev.KeyStrategy != next.KeyStrategy ||
+		prev.KeyQuarantine != next.KeyQuarantine
+}
+
+// reloadableChanges lista as opções recarregáveis que foram alteradas
+// Os valores não são incluídos, pois as chaves da WeatherAPI são segredos
+func reloadableChanges(prev, next config.ServiceB) []string {
+	var changed []string
+	if prev.WeatherAPIKey != next.WeatherAPIKey {
+		changed = append(changed, "weather_api_key")
+	}
+	if !slices.Equal(prev.WeatherAPIKeys, next.WeatherAPIKeys) {
+		changed = append(changed, "weather_api_keys")
+	}
+	if prev.KeyStrategy != next.KeyStrategy {
+		changed = append(changed, "key_strategy")
+	}
+	if prev.KeyQuarantine != next.KeyQuarantine {
+		changed = append(changed, "key_quarantine")
+	}
+	if prev.SamplerRatio != next.SamplerRatio {
+		changed = append(changed, "sampler_ratio")
+	}
+	if prev.Cache.LocationTTL != next.Cache.LocationTTL {
+		changed = append(changed, "cache.location_ttl")
+	}
+	if prev.Cache.WeatherTTL != next.Cache.WeatherTTL {
+		changed = append(changed, "cache.weather_ttl")
+	}
+	if prev.Cache.MaxEntries != next.Cache.MaxEntries {
+		changed = append(changed, "cache.max_entries")
+	}
+	if prev.Cache.HistoryMaxEntries != next.Cache.HistoryMaxEntries {
+		changed = append(changed, "cache.history_max_entries")
+	}
+	if prev.RateLimits.ViaCEP != next.RateLimits.ViaCEP {
+		changed = append(changed, "rate_limits.viacep")
+	}
+	if prev.RateLimits.WeatherAPI != next.RateLimits.WeatherAPI {
+		changed = append(changed, "rate_limits.weatherapi")
+	}
+	if !slices.Equal(prev.AirQualityProviders, next.AirQualityProviders) {
+		changed = append(changed, "air_quality_providers")
+	}
+	return changed
+}
+
+// restartRequired lista as opções alteradas que só valem após reinício
+func restartRequired(prev, next config.ServiceB) []string {
+	var restart []string
+	for _, o := range []struct {
+		name    string
+		changed bool
+	}{
+		{"port", prev.Port != next.Port},
+		{"grpc_port", prev.GRPCPort != next.GRPCPort},
+		{"zipkin_url", prev.ZipkinURL != next.ZipkinURL},
+		{"legacy_errors", prev.LegacyErrors != next.LegacyErrors},
+		{"batch_concurrency", prev.BatchConcurrency != next.BatchConcurrency},
+		{"reload_interval", prev.ReloadInterval != next.ReloadInterval},
+		{"units", prev.Units != next.Units},
+		{"propagators", !slices.Equal(prev.Propagators, next.Propagators)},
+		{"baggage_attributes", !slices.Equal(prev.BaggageAttributes, next.BaggageAttributes)},
+		// O conteúdo dos certificados é recarregado pelo certs.Reloader; apenas
+		// os caminhos e a ativação exigem reinício
+		{"tls", prev.TLS != next.TLS},
+		{"metrics", prev.Metrics != next.Metrics},
+	} {
+		if o.changed {
+			restart = append(restart, o.name)
+		}
+	}
+	return restart
+}
+
+// watch recarrega a configuração ao receber SIGHUP e, quando há arquivo de
+// configuração e reload_interval é positivo, sempre que o arquivo for alterado
+// Retorna imediatamente; as verificações terminam quando ctx é cancelado
+func (rl *reloader) watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				rl.reload("sighup")
+			}
+		}
+	}()
+
+	interval := time.Duration(rl.current.Load().ReloadInterval)
+	if rl.path != "" && interval > 0 {
+		go config.Watch(ctx, rl.path, interval, func() { rl.reload("file") })
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"cep-weather/internal/config"
+	"cep-weather/internal/telemetry"
+	"cep-weather/internal/weather"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	t.Setenv("WEATHER_API_KEY", "")
+	t.Cleanup(func() {
+		weather.SetAPIKey("")
+		telemetry.SetSampleRatio(1)
+	})
+
+	path := filepath.Join(t.TempDir(), "service-b.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("weather_api_key: old-key\n")
+	cfg := config.DefaultServiceB()
+	if err := config.Load(&cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	rl := newReloader(path, cfg, newResolver(time.Minute, time.Minute))
+
+	// A chave, a amostragem, os caches e a ordem dos provedores são aplicados; a porta exige reinício
+	write("weather_api_key: new-key\nsampler_ratio: 0.5\nport: \"9090\"\ncache:\n  weather_ttl: 30s\n  max_entries: 500\nair_quality_providers: [open-meteo, weatherapi]\n")
+	if err := rl.reload("file"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	current := rl.current.Load()
+	if current.WeatherAPIKey.Value() != "new-key" || current.SamplerRatio != 0.5 || time.Duration(current.Cache.WeatherTTL) != 30*time.Second || current.Cache.MaxEntries != 500 {
+		t.Errorf("reloadable options not applied: %+v", current)
+	}
+	if providers := *rl.rs.airQualityProviders.Load(); len(providers) != 2 || providers[0] != weather.OpenMeteoAirQuality {
+		t.Errorf("expected air quality provider order to be reloaded, got %v", providers)
+	}
+	if current.Port != "8081" {
+		t.Errorf("expected port to require a restart, got %q", current.Port)
+	}
+
+	// Uma configuração inválida é rejeitada e a atual é mantida
+	write("weather_api_key: other-key\nsampler_ratio: 2\n")
+	if err := rl.reload("sighup"); err == nil {
+		t.Fatalf("expected invalid configuration to be rejected")
+	}
+	if rl.current.Load().WeatherAPIKey.Value() != "new-key" {
+		t.Errorf("expected previous configuration to be kept, got %+v", rl.current.Load())
+	}
+}
+
+func TestShadowedByEnv(t *testing.T) {
+	t.Setenv("WEATHER_CACHE_TTL", "1m")
+	t.Setenv("WEATHERAPI_RATE_LIMIT", "5")
+	t.Setenv("PORT", "9090") // Exige reinício de qualquer forma
+
+	got := shadowedByEnv(config.DefaultServiceB())
+	want := []string{"cache.weather_ttl (WEATHER_CACHE_TTL)", "rate_limits.weatherapi.rate (WEATHERAPI_RATE_LIMIT)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("shadowedByEnv = %v, want %v", got, want)
+	}
+}
+
+func TestReloadableChanges(t *testing.T) {
+	prev := config.DefaultServiceB()
+	next := prev
+	next.WeatherAPIKey = "rotated"
+	next.Cache.LocationTTL = config.Duration(time.Hour)
+	next.Units.Precision = 1
+
+	if got, want := reloadableChanges(prev, next), []string{"weather_api_key", "cache.location_ttl"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reloadableChanges = %v, want %v", got, want)
+	}
+	if got, want := restartRequired(prev, next), []string{"units"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("restartRequired = %v, want %v", got, want)
+	}
+}
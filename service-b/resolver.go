@@ -0,0 +1,332 @@
+package main
+
+import (
+	"cep-weather/internal/api"
+	"cep-weather/internal/cache"
+	"cep-weather/internal/cep"
+	"cep-weather/internal/i18n"
+	"cep-weather/internal/location"
+	"cep-weather/internal/problem"
+	"cep-weather/internal/ratelimit"
+	"cep-weather/internal/units"
+	"cep-weather/internal/weather"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resolver concentra o pipeline de consulta do Serviço B:
+// CEP -> localização (ViaCEP) -> temperatura (WeatherAPI) -> resposta
+//
+// Os resultados das APIs externas ficam em cache: localizações por CEP e
+// temperaturas por cidade. Consultas concorrentes para a mesma chave (por
+// exemplo, CEPs da mesma cidade em um lote) resultam em uma única chamada.
+type resolver struct {
+	locations    *cache.Cache[cep.CEP, location.Location]         // Localizações por CEP
+	conditions   *cache.Cache[string, weather.Conditions]         // Condições atuais por cidade
+	forecasts    *cache.Cache[forecastKey, []weather.ForecastDay] // Previsões por cidade e quantidade de dias
+	airQualities *cache.Cache[string, weather.AirQuality]         // Qualidade do ar por cidade
+	alerts       *cache.Cache[string, []weather.Alert]            // Alertas ativos por cidade
+	history      *cache.Cache[historyKey, weather.HistoryDay]     // Histórico por cidade e dia (sem expiração, limitado por LRU)
+	// Provedores de qualidade do ar, consultados em ordem até o primeiro
+	// sucesso; a ordem pode ser trocada na recarga da configuração
+	airQualityProviders atomic.Pointer[[]weather.AirQualityProvider]
+}
+
+// historyKey identifica um dia do histórico em cache
+type historyKey struct {
+	city string
+	date string
+}
+
+// forecastKey identifica uma previsão em cache
+type forecastKey struct {
+	city string
+	days int
+}
+
+// newResolver cria o pipeline de consulta com os tempos de expiração informados
+//
+// Parâmetros:
+//   - locationTTL: Validade das localizações em cache (CEPs raramente mudam)
+//   - weatherTTL: Validade das condições atuais, previsões, qualidade do ar e alertas em cache
+func newResolver(locationTTL, weatherTTL time.Duration) *resolver {
+	rs := &resolver{
+		locations:    cache.New[cep.CEP, location.Location](locationTTL),
+		conditions:   cache.New[string, weather.Conditions](weatherTTL),
+		forecasts:    cache.New[forecastKey, []weather.ForecastDay](weatherTTL),
+		airQualities: cache.New[string, weather.AirQuality](weatherTTL),
+		alerts:       cache.New[string, []weather.Alert](weatherTTL),
+		// Dias passados não mudam: o histórico é mantido indefinidamente (ttl 0)
+		history: cache.New[historyKey, weather.HistoryDay](0),
+	}
+	rs.setAirQualityProviders([]weather.AirQualityProvider{weather.WeatherAPIAirQuality, weather.OpenMeteoAirQuality})
+	return rs
+}
+
+// setAirQualityProviders define os provedores de qualidade do ar e a ordem de consulta
+// Consultas em andamento continuam com a lista lida no início da consulta
+func (rs *resolver) setAirQualityProviders(providers []weather.AirQualityProvider) {
+	rs.airQualityProviders.Store(&providers)
+}
+
+// setTTLs altera a validade dos resultados armazenados daqui em diante
+// Usado na recarga da configuração; resultados já em cache mantêm a validade original
+func (rs *resolver) setTTLs(locationTTL, weatherTTL time.Duration) {
+	rs.locations.SetTTL(locationTTL)
+	rs.conditions.SetTTL(weatherTTL)
+	rs.forecasts.SetTTL(weatherTTL)
+	rs.airQualities.SetTTL(weatherTTL)
+	rs.alerts.SetTTL(weatherTTL)
+}
+
+// setMaxEntries limita o número de entradas de cada cache
+// Ao atingir o limite, os resultados usados há mais tempo são descartados.
+// O histórico, que não expira, tem um limite próprio (historyN).
+func (rs *resolver) setMaxEntries(n, historyN int) {
+	rs.locations.SetMaxEntries(n)
+	rs.conditions.SetMaxEntries(n)
+	rs.forecasts.SetMaxEntries(n)
+	rs.airQualities.SetMaxEntries(n)
+	rs.alerts.SetMaxEntries(n)
+	rs.history.SetMaxEntries(historyN)
+}
+
+// location consulta a localização do CEP, usando o cache quando possível
+// IMPORTANTE: A função GetLocationByCEP cria um span interno para medir
+// o tempo de resposta da chamada externa à API ViaCEP
+func (rs *resolver) location(ctx context.Context, c cep.CEP) (location.Location, error) {
+	loc, hit, err := rs.locations.GetOrLoad(ctx, c, func(ctx context.Context) (location.Location, error) {
+		return location.GetLocationByCEP(ctx, c.String())
+	})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.location.hit", hit))
+	return loc, err
+}
+
+// currentConditions consulta as condições atuais da cidade, usando o cache quando possível
+// IMPORTANTE: A função GetConditions cria um span interno para medir
+// o tempo de resposta da chamada externa à API WeatherAPI
+func (rs *resolver) currentConditions(ctx context.Context, city string) (weather.Conditions, error) {
+	cond, hit, err := rs.conditions.GetOrLoad(ctx, city, func(ctx context.Context) (weather.Conditions, error) {
+		return weather.GetConditions(ctx, city)
+	})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.weather.hit", hit))
+	return cond, err
+}
+
+// weather executa o pipeline completo para um CEP já validado
+// - Consulta à API ViaCEP (com span de rastreamento)
+// - Consulta à API WeatherAPI (com span de rastreamento)
+// - Conversão de temperaturas
+//
+// Com extended, a resposta inclui as condições detalhadas (sensação térmica,
+// umidade, vento, índice UV e descrição), obtidas na mesma chamada à WeatherAPI
+func (rs *resolver) weather(ctx context.Context, c cep.CEP, extended bool) (api.WeatherResponse, error) {
+	loc, err := rs.location(ctx, c)
+	if err != nil {
+		return api.WeatherResponse{}, err
+	}
+
+	cond, err := rs.currentConditions(ctx, loc.City)
+	if err != nil {
+		return api.WeatherResponse{}, err
+	}
+
+	// Calcula as conversões de temperatura conforme fórmulas especificadas
+	// (ver api.NewTemperature) e monta a resposta no formato dos requisitos
+	resp := api.NewWeatherResponse(loc.City, cond.TempC)
+	if extended {
+		resp.Conditions = &api.Conditions{
+			FeelsLike: api.NewTemperature(cond.FeelsLikeC),
+			Humidity:  cond.Humidity,
+			Wind:      api.NewWind(cond.WindKph, cond.WindDir),
+			Pressure:  api.NewPressure(cond.PressureMB),
+			UV:        cond.UV,
+			// A descrição vem em inglês da WeatherAPI e é traduzida para o
+			// idioma negociado com o cliente; o cache guarda sempre o original
+			Text: i18n.T(i18n.FromContext(ctx), cond.Text),
+		}
+	}
+	return resp, nil
+}
+
+// forecast consulta a previsão dos próximos dias para um CEP já validado
+// As temperaturas mínima e máxima de cada dia recebem as mesmas conversões
+// da resposta de clima atual
+func (rs *resolver) forecast(ctx context.Context, c cep.CEP, days int) (api.ForecastResponse, error) {
+	loc, err := rs.location(ctx, c)
+	if err != nil {
+		return api.ForecastResponse{}, err
+	}
+
+	key := forecastKey{city: loc.City, days: days}
+	forecast, hit, err := rs.forecasts.GetOrLoad(ctx, key, func(ctx context.Context) ([]weather.ForecastDay, error) {
+		return weather.GetForecast(ctx, loc.City, days)
+	})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.forecast.hit", hit))
+	if err != nil {
+		return api.ForecastResponse{}, err
+	}
+
+	resp := api.ForecastResponse{City: loc.City, Days: make([]api.ForecastDay, len(forecast))}
+	for i, d := range forecast {
+		resp.Days[i] = api.ForecastDay{
+			Date:         d.Date,
+			Min:          api.NewTemperature(d.MinTempC),
+			Max:          api.NewTemperature(d.MaxTempC),
+			ChanceOfRain: d.ChanceOfRain,
+			PrecipMM:     d.PrecipMM,
+		}
+	}
+	return resp, nil
+}
+
+// airQuality consulta a qualidade do ar atual para um CEP já validado
+// O índice da US EPA é acompanhado da categoria correspondente
+func (rs *resolver) airQuality(ctx context.Context, c cep.CEP) (api.AirQualityResponse, error) {
+	loc, err := rs.location(ctx, c)
+	if err != nil {
+		return api.AirQualityResponse{}, err
+	}
+
+	aq, hit, err := rs.airQualities.GetOrLoad(ctx, loc.City, func(ctx context.Context) (weather.AirQuality, error) {
+		return rs.loadAirQuality(ctx, loc.City)
+	})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.air_quality.hit", hit))
+	if err != nil {
+		return api.AirQualityResponse{}, err
+	}
+
+	return api.AirQualityResponse{
+		City:     loc.City,
+		PM25:     units.Round(aq.PM25),
+		PM10:     units.Round(aq.PM10),
+		O3:       units.Round(aq.O3),
+		Index:    aq.USEPAIndex,
+		Category: api.AirQualityCategory(aq.USEPAIndex),
+		UV:       aq.UV,
+	}, nil
+}
+
+// loadAirQuality consulta os provedores de qualidade do ar em ordem e devolve
+// o resultado do primeiro que responder; o provedor usado fica no atributo
+// air_quality.provider do span. Se todos falharem, os erros são combinados,
+// preservando a classificação de kindFor (ex: chaves em quarentena).
+func (rs *resolver) loadAirQuality(ctx context.Context, city string) (weather.AirQuality, error) {
+	span := trace.SpanFromContext(ctx)
+	var errs []error
+	for _, p := range *rs.airQualityProviders.Load() {
+		aq, err := p.AirQuality(ctx, city)
+		if err == nil {
+			span.SetAttributes(attribute.String("air_quality.provider", p.Name()))
+			return aq, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return weather.AirQuality{}, errors.Join(errs...)
+}
+
+// activeAlerts consulta os alertas meteorológicos ativos para um CEP já validado
+// A ausência de alertas resulta em uma lista vazia, não em erro
+func (rs *resolver) activeAlerts(ctx context.Context, c cep.CEP) (api.AlertsResponse, error) {
+	loc, err := rs.location(ctx, c)
+	if err != nil {
+		return api.AlertsResponse{}, err
+	}
+
+	alerts, hit, err := rs.alerts.GetOrLoad(ctx, loc.City, func(ctx context.Context) ([]weather.Alert, error) {
+		return weather.GetAlerts(ctx, loc.City)
+	})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.alerts.hit", hit))
+	if err != nil {
+		return api.AlertsResponse{}, err
+	}
+
+	resp := api.AlertsResponse{City: loc.City, Alerts: make([]api.Alert, len(alerts))}
+	for i, a := range alerts {
+		resp.Alerts[i] = api.Alert{
+			Event:       a.Event,
+			Severity:    a.Severity,
+			Headline:    a.Headline,
+			Start:       formatTime(a.Start),
+			End:         formatTime(a.End),
+			Description: a.Description,
+		}
+	}
+	return resp, nil
+}
+
+// pastWeather consulta as temperaturas registradas em um dia passado para um CEP já validado
+//
+// Parâmetros:
+//   - date: Dia local (AAAA-MM-DD), já validado por api.ParseHistoryDate
+//   - at: Instante pedido pelo cliente, no fuso da localização; quando
+//     informado, a resposta inclui a temperatura daquela hora
+//   - loc: Fuso horário da localização
+func (rs *resolver) pastWeather(ctx context.Context, c cep.CEP, date string, at time.Time, loc *time.Location) (api.HistoryResponse, error) {
+	l, err := rs.location(ctx, c)
+	if err != nil {
+		return api.HistoryResponse{}, err
+	}
+
+	day, hit, err := rs.history.GetOrLoad(ctx, historyKey{city: l.City, date: date}, func(ctx context.Context) (weather.HistoryDay, error) {
+		return weather.GetHistory(ctx, l.City, date)
+	})
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.history.hit", hit))
+	if err != nil {
+		return api.HistoryResponse{}, err
+	}
+
+	resp := api.HistoryResponse{
+		City:     l.City,
+		Date:     day.Date,
+		TimeZone: loc.String(),
+		Min:      api.NewTemperature(day.MinTempC),
+		Max:      api.NewTemperature(day.MaxTempC),
+		Avg:      api.NewTemperature(day.AvgTempC),
+	}
+	if !at.IsZero() {
+		// Os horários da WeatherAPI são locais e marcam o início de cada hora
+		hour := time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), 0, 0, 0, loc)
+		for _, h := range day.Hours {
+			if h.Time == hour.Format("2006-01-02 15:04") {
+				resp.At = &api.HistoryHour{Time: hour.Format(time.RFC3339), Temperature: api.NewTemperature(h.TempC)}
+				break
+			}
+		}
+	}
+	return resp, nil
+}
+
+// formatTime formata um horário em RFC 3339, preservando o fuso horário
+// informado pela fonte; o horário zero resulta em texto vazio
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// kindFor define a categoria de erro devolvida ao cliente para uma falha do pipeline
+// Requisito: Retorna 404 se CEP não for encontrado; demais falhas resultam em 500
+// (ou 503 quando nenhuma chave da WeatherAPI está disponível ou o limite de taxa foi atingido)
+func kindFor(err error) problem.Kind {
+	if errors.Is(err, location.ErrNotFound) {
+		return problem.ZipcodeNotFound
+	}
+	// Todas as chaves da WeatherAPI foram recusadas e estão em quarentena, ou
+	// o limite de taxa de uma API externa impediu a chamada a tempo
+	var limited *ratelimit.RateLimited
+	if errors.Is(err, weather.ErrKeysQuarantined) || errors.As(err, &limited) {
+		return problem.UpstreamUnavailable
+	}
+	return problem.Internal
+}
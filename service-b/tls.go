@@ -0,0 +1,43 @@
+package main
+
+import (
+	"cep-weather/internal/certs"
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// peerSubjectKey é o atributo do span com o subject do certificado do cliente
+// (ex: "CN=service-a,O=cep-weather"), presente quando o TLS mútuo está ativo
+const peerSubjectKey = attribute.Key("tls.client.subject")
+
+// tlsPeer registra no span da requisição HTTP o certificado do cliente
+func tlsPeer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recordPeer(r.Context(), r.TLS)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// peerInterceptor registra no span da chamada gRPC o certificado do cliente
+func peerInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			recordPeer(ctx, &info.State)
+		}
+	}
+	return handler(ctx, req)
+}
+
+// recordPeer adiciona o subject do certificado do cliente ao span atual
+func recordPeer(ctx context.Context, cs *tls.ConnectionState) {
+	if subject := certs.PeerSubject(cs); subject != "" {
+		trace.SpanFromContext(ctx).SetAttributes(peerSubjectKey.String(subject))
+	}
+}
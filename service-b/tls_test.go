@@ -0,0 +1,80 @@
+package main
+
+import (
+	"cep-weather/internal/api/weatherpb"
+	"cep-weather/internal/certs"
+	"cep-weather/internal/certs/certstest"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func TestGRPC_MutualTLS(t *testing.T) {
+	fakeUpstreams(t)
+	sr := tracetest.NewSpanRecorder()
+	orig := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(orig) })
+
+	dir := t.TempDir()
+	ca := certstest.NewCA(t, "test-ca")
+	caFile := ca.WriteCA(t, dir)
+	serverCert, serverKey := ca.Issue(t, "service-b").Write(t, dir, "service-b")
+	clientCert, clientKey := ca.Issue(t, "service-a").Write(t, dir, "service-a")
+	server, err := certs.NewReloader(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := certs.NewReloader(clientCert, clientKey, caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newGRPCServer(newResolver(time.Minute, time.Minute), 4, server.ServerConfig())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	dial := func(cfg *certs.Reloader) weatherpb.WeatherServiceClient {
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(cfg.ClientConfig("localhost"))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return weatherpb.NewWeatherServiceClient(conn)
+	}
+
+	if _, err := dial(client).GetWeather(context.Background(), &weatherpb.GetWeatherRequest{Cep: "01310-000"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var subject string
+	for _, s := range sr.Ended() {
+		for _, kv := range s.Attributes() {
+			if kv.Key == peerSubjectKey {
+				subject = kv.Value.AsString()
+			}
+		}
+	}
+	if subject != "CN=service-a,O=cep-weather" {
+		t.Fatalf("expected client subject on span, got %q", subject)
+	}
+
+	// Sem certificado de cliente, a chamada é recusada no handshake
+	anonymous, err := certs.NewReloader("", "", caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dial(anonymous).GetWeather(context.Background(), &weatherpb.GetWeatherRequest{Cep: "01310-000"}); err == nil {
+		t.Fatalf("expected handshake failure without client certificate")
+	}
+}